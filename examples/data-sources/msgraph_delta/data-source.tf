@@ -0,0 +1,14 @@
+variable "users_delta_link" {
+  type    = string
+  default = null
+}
+
+data "msgraph_delta" "users" {
+  collection = "users"
+  select     = ["id", "displayName", "accountEnabled"]
+  delta_link = var.users_delta_link
+}
+
+output "users_delta_link" {
+  value = data.msgraph_delta.users.next_delta_link
+}
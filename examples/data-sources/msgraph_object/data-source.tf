@@ -1,3 +1,8 @@
 data "msgraph_object" "me" {
   id = "me"
 }
+
+data "msgraph_object" "graph" {
+  collection = "servicePrincipals"
+  filter     = "appId eq '00000003-0000-0000-c000-000000000000'"
+}
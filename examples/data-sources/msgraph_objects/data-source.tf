@@ -0,0 +1,6 @@
+data "msgraph_objects" "teams" {
+  collection = "groups"
+  filter     = "startswith(displayName,'team-')"
+  select     = ["id", "displayName"]
+  order_by   = ["displayName"]
+}
@@ -0,0 +1,13 @@
+resource "msgraph_action" "password" {
+  action = "${msgraph_object.application.id}/addPassword"
+  body = {
+    "passwordCredential" = {
+      "displayName" = "terraform"
+    }
+  }
+
+  undo_action = "${msgraph_object.application.id}/removePassword"
+  undo_output_mapping = {
+    "keyId" = "keyId"
+  }
+}
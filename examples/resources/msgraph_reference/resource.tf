@@ -0,0 +1,5 @@
+resource "msgraph_reference" "owner" {
+  object     = msgraph_object.group.id
+  navigation = "owners"
+  target_id  = "00000000-0000-0000-0000-000000000000"
+}
@@ -0,0 +1,8 @@
+resource "msgraph_references" "members" {
+  object     = msgraph_object.group.id
+  navigation = "members"
+  target_ids = [
+    "00000000-0000-0000-0000-000000000001",
+    "00000000-0000-0000-0000-000000000002",
+  ]
+}
@@ -0,0 +1,7 @@
+resource "msgraph_update" "authorization_policy" {
+  id = "policies/authorizationPolicy"
+  properties = {
+    "allowInvitesFrom" = "adminsAndGuestInviters"
+  }
+  restore_on_destroy = true
+}
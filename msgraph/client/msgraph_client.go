@@ -7,7 +7,13 @@ import (
 	"github.com/hashicorp/terraform-plugin-framework/types"
 )
 
+// GraphBaseURL is the root URL of Microsoft Graph, ending with a slash.
+const GraphBaseURL = "https://graph.microsoft.com/"
+
 type MsGraphClient interface {
 	GetToken(context context.Context) (string, error)
 	R(context context.Context, apiVersion types.String) *resty.Request
+	// BaseURL returns the absolute URL of the API version, ending with a slash,
+	// falling back to the provider's API version when `apiVersion` is null.
+	BaseURL(apiVersion types.String) string
 }
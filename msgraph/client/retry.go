@@ -0,0 +1,21 @@
+package client
+
+import "context"
+
+// RetryProbeFunc reports whether a failed non-idempotent request can safely be
+// retried, typically because it proved that the object it creates does not exist.
+type RetryProbeFunc func(ctx context.Context) bool
+
+type retryProbeKey struct{}
+
+// WithRetryProbe returns a context that allows non-idempotent requests made with
+// it to be retried on server errors when `probe` returns true.
+func WithRetryProbe(ctx context.Context, probe RetryProbeFunc) context.Context {
+	return context.WithValue(ctx, retryProbeKey{}, probe)
+}
+
+// RetryProbe returns the probe attached to the context by WithRetryProbe.
+func RetryProbe(ctx context.Context) (RetryProbeFunc, bool) {
+	probe, ok := ctx.Value(retryProbeKey{}).(RetryProbeFunc)
+	return probe, ok
+}
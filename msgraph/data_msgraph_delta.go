@@ -0,0 +1,129 @@
+package msgraph
+
+import (
+	"context"
+	"regexp"
+
+	"github.com/GoodCloudWorks/terraform-provider-msgraph/msgraph/client"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+var (
+	_ datasource.DataSource              = &msGraphDeltaDataSource{}
+	_ datasource.DataSourceWithConfigure = &msGraphDeltaDataSource{}
+)
+
+type msGraphDeltaDataSource struct {
+	client client.MsGraphClient
+}
+
+type msGraphDeltaDataSourceModel struct {
+	Collection    types.String  `tfsdk:"collection"`
+	ApiVersion    types.String  `tfsdk:"api_version"`
+	Filter        types.String  `tfsdk:"filter"`
+	Select        types.List    `tfsdk:"select"`
+	DeltaLink     types.String  `tfsdk:"delta_link"`
+	NextDeltaLink types.String  `tfsdk:"next_delta_link"`
+	Output        types.Dynamic `tfsdk:"output"`
+}
+
+func NewMsGraphDeltaDataSource() datasource.DataSource {
+	return &msGraphDeltaDataSource{}
+}
+
+func (r *msGraphDeltaDataSource) Configure(ctx context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	if v, ok := req.ProviderData.(client.MsGraphClient); ok {
+		r.client = v
+	}
+}
+
+func (r *msGraphDeltaDataSource) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_delta"
+}
+
+func (r *msGraphDeltaDataSource) Schema(ctx context.Context, req datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "This data source provides access to the changes of a Microsoft Graph collection using a delta query.",
+		Attributes: map[string]schema.Attribute{
+			"collection": schema.StringAttribute{
+				Required:    true,
+				Description: "The collection to track changes of, e.g. `users`.",
+			},
+
+			"api_version": schema.StringAttribute{
+				Optional:    true,
+				Description: "Override the provider Microsoft Graph API version.",
+			},
+
+			"filter": schema.StringAttribute{
+				Optional:    true,
+				Description: "The OData `$filter` expression used to filter the objects. Ignored when `delta_link` is specified.",
+			},
+
+			"select": schema.ListAttribute{
+				Optional:    true,
+				ElementType: types.StringType,
+				Description: "The properties of the objects to track. Ignored when `delta_link` is specified.",
+			},
+
+			"delta_link": schema.StringAttribute{
+				Optional:    true,
+				Description: "The `next_delta_link` of a previous query. When specified, only the changes since that query are retrieved.",
+				Validators: []validator.String{
+					stringvalidator.RegexMatches(regexp.MustCompile("^"+regexp.QuoteMeta(client.GraphBaseURL)), "must be a Microsoft Graph delta link"),
+				},
+			},
+
+			"next_delta_link": schema.StringAttribute{
+				Computed:    true,
+				Description: "The delta link to pass as `delta_link` to retrieve the changes made after this query.",
+			},
+
+			"output": schema.DynamicAttribute{
+				Computed:    true,
+				Description: "The changed objects retrieved from Microsoft Graph. Deleted objects have an `@removed` property.",
+			},
+		},
+	}
+}
+
+func (r *msGraphDeltaDataSource) Read(ctx context.Context, request datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var model msGraphDeltaDataSourceModel
+	resp.Diagnostics.Append(request.Config.Get(ctx, &model)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	path, diags := ensureIsValidPathString(model.Collection)
+	if diags.HasError() {
+		resp.Diagnostics.Append(diags...)
+		return
+	}
+
+	http := r.client.R(ctx, model.ApiVersion)
+
+	url, isLink := path+"/delta", false
+	if !model.DeltaLink.IsNull() {
+		url, isLink = model.DeltaLink.ValueString(), true
+	} else {
+		odataQuery{
+			Filter: model.Filter.ValueString(),
+			Select: listAsStrings(model.Select),
+		}.apply(http)
+	}
+
+	content, deltaLink, diags := ensureListDeltaAsDynamic(http, url, isLink)
+	if diags.HasError() {
+		resp.Diagnostics.Append(diags...)
+		return
+	}
+
+	model.Output = content
+	model.NextDeltaLink = types.StringValue(deltaLink)
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, model)...)
+}
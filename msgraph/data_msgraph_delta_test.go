@@ -0,0 +1,44 @@
+package msgraph
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+func TestAccMsGraphDeltaDataSource(t *testing.T) {
+	const resourceName = "data.msgraph_delta.groups"
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: protoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: defaultProviderConfigWith(`
+					data "msgraph_delta" "groups" {
+						collection = "groups"
+						select     = ["id", "displayName"]
+					}
+					`),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttrSet(resourceName, "next_delta_link"),
+					resource.TestCheckResourceAttrSet(resourceName, "output.#"),
+				),
+			},
+			{
+				Config: defaultProviderConfigWith(`
+					data "msgraph_delta" "initial" {
+						collection = "groups"
+						select     = ["id", "displayName"]
+					}
+
+					data "msgraph_delta" "groups" {
+						collection = "groups"
+						delta_link = data.msgraph_delta.initial.next_delta_link
+					}
+					`),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttrSet(resourceName, "next_delta_link"),
+				),
+			},
+		},
+	})
+}
@@ -2,10 +2,17 @@ package msgraph
 
 import (
 	"context"
+	"fmt"
 
 	"github.com/GoodCloudWorks/terraform-provider-msgraph/msgraph/client"
+	"github.com/GoodCloudWorks/terraform-provider-msgraph/msgraph/id"
+	"github.com/go-resty/resty/v2"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
 	"github.com/hashicorp/terraform-plugin-framework/datasource"
 	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 )
 
@@ -22,6 +29,8 @@ type msGraphObjectDataSourceModel struct {
 	ID         types.String  `tfsdk:"id"`
 	ApiVersion types.String  `tfsdk:"api_version"`
 	Collection types.String  `tfsdk:"collection"`
+	Filter     types.String  `tfsdk:"filter"`
+	ObjectID   types.String  `tfsdk:"object_id"`
 	Output     types.Dynamic `tfsdk:"output"`
 }
 
@@ -44,13 +53,31 @@ func (r *msGraphObjectDataSource) Schema(ctx context.Context, req datasource.Sch
 		Description: "This data source provides access to Microsoft Graph objects.",
 		Attributes: map[string]schema.Attribute{
 			"id": schema.StringAttribute{
-				Required:    true,
-				Description: "The ID of the object to retrieve.",
+				Optional:    true,
+				Computed:    true,
+				Description: "The ID of the object to retrieve. Exactly one of `id` or `filter` must be specified.",
+				Validators: []validator.String{
+					stringvalidator.ExactlyOneOf(path.MatchRoot("filter")),
+				},
 			},
 
 			"collection": schema.StringAttribute{
+				Optional:    true,
 				Computed:    true,
-				Description: "The collection of the object to retrieve.",
+				Description: "The collection of the object to retrieve. Required when `filter` is specified.",
+			},
+
+			"filter": schema.StringAttribute{
+				Optional:    true,
+				Description: "The OData `$filter` expression used to find the object in `collection`, e.g. `appId eq '...'`. Exactly one object must match.",
+				Validators: []validator.String{
+					stringvalidator.AlsoRequires(path.MatchRoot("collection")),
+				},
+			},
+
+			"object_id": schema.StringAttribute{
+				Computed:    true,
+				Description: "The object ID of the object retrieved.",
 			},
 
 			"api_version": schema.StringAttribute{
@@ -73,22 +100,61 @@ func (r *msGraphObjectDataSource) Read(ctx context.Context, request datasource.R
 		return
 	}
 
-	id, diags := ensureParseIDString(model.ID)
+	http := r.client.R(ctx, model.ApiVersion)
+
+	var id *id.ID
+	var diags diag.Diagnostics
+	if model.Filter.IsNull() {
+		id, diags = ensureParseIDString(model.ID)
+	} else {
+		id, diags = ensureFindObjectID(http, model.Collection, model.Filter)
+	}
 	if diags.HasError() {
 		resp.Diagnostics.Append(diags...)
 		return
 	}
 
-	http := r.client.R(ctx, model.ApiVersion)
+	if model.ID.IsNull() {
+		model.ID = id.AsString()
+	}
 
-	content, diags := ensureGetObjectAsDynamic(http, id.Path)
+	content, diags := ensureGetObjectAsDynamic(r.client.R(ctx, model.ApiVersion), id.Path)
 	if diags.HasError() {
 		resp.Diagnostics.Append(diags...)
 		return
 	}
 
 	model.Output = content
-	model.Collection = types.StringValue(id.Collection())
+	model.ObjectID = types.StringValue(id.ObjectId())
+	if model.Collection.IsNull() {
+		model.Collection = types.StringValue(id.Collection())
+	}
 
 	resp.Diagnostics.Append(resp.State.Set(ctx, model)...)
 }
+
+// ensureFindObjectID returns the ID of the single object in `collection`
+// matching `filter`.
+func ensureFindObjectID(http *resty.Request, collection types.String, filter types.String) (*id.ID, diag.Diagnostics) {
+	path, diags := ensureIsValidPathString(collection)
+	if diags.HasError() {
+		return nil, diags
+	}
+
+	// Two results are enough to tell whether the filter is ambiguous.
+	odataQuery{Filter: filter.ValueString(), Top: 2}.apply(http)
+
+	objectIDs, diags := ensureListObjectIDs(http, path, 2)
+	if diags.HasError() {
+		return nil, diags
+	}
+
+	switch len(objectIDs) {
+	case 0:
+		return nil, errorDiagnostics(fmt.Sprintf("No object found in %q.", path), fmt.Sprintf("No object matches the filter: %s", filter.ValueString()))
+	case 1:
+		return id.New(path, objectIDs[0]), noErrors()
+	default:
+		return nil, errorDiagnostics(fmt.Sprintf("Multiple objects found in %q.", path), fmt.Sprintf("More than one object matches the filter: %s", filter.ValueString()))
+	}
+}
@@ -35,6 +35,20 @@ func TestAccMsGraphObjectDataSource(t *testing.T) {
 					resource.TestCheckResourceAttr(resourceName, "output.@odata.context", "https://graph.microsoft.com/beta/$metadata#organization/$entity"),
 				),
 			},
+			{
+				Config: defaultProviderConfigWith(`
+					data "msgraph_provider_config" "this" {}
+					data "msgraph_object" "organization" {
+						collection = "organization"
+						filter     = "id eq '${data.msgraph_provider_config.this.tenant_id}'"
+					}
+					`),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttrPair(resourceName, "object_id", "data.msgraph_provider_config.this", "tenant_id"),
+					resource.TestCheckResourceAttr(resourceName, "collection", "organization"),
+					resource.TestCheckResourceAttrSet(resourceName, "id"),
+				),
+			},
 		},
 	})
 }
@@ -0,0 +1,149 @@
+package msgraph
+
+import (
+	"context"
+
+	"github.com/GoodCloudWorks/terraform-provider-msgraph/msgraph/client"
+	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+var (
+	_ datasource.DataSource              = &msGraphObjectsDataSource{}
+	_ datasource.DataSourceWithConfigure = &msGraphObjectsDataSource{}
+)
+
+type msGraphObjectsDataSource struct {
+	client client.MsGraphClient
+}
+
+type msGraphObjectsDataSourceModel struct {
+	Collection types.String  `tfsdk:"collection"`
+	ApiVersion types.String  `tfsdk:"api_version"`
+	Filter     types.String  `tfsdk:"filter"`
+	Select     types.List    `tfsdk:"select"`
+	Expand     types.String  `tfsdk:"expand"`
+	OrderBy    types.List    `tfsdk:"order_by"`
+	Search     types.String  `tfsdk:"search"`
+	Top        types.Int64   `tfsdk:"top"`
+	MaxItems   types.Int64   `tfsdk:"max_items"`
+	Output     types.Dynamic `tfsdk:"output"`
+}
+
+func NewMsGraphObjectsDataSource() datasource.DataSource {
+	return &msGraphObjectsDataSource{}
+}
+
+func (r *msGraphObjectsDataSource) Configure(ctx context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	if v, ok := req.ProviderData.(client.MsGraphClient); ok {
+		r.client = v
+	}
+}
+
+func (r *msGraphObjectsDataSource) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_objects"
+}
+
+func (r *msGraphObjectsDataSource) Schema(ctx context.Context, req datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "This data source provides access to a list of objects in a Microsoft Graph collection.",
+		Attributes: map[string]schema.Attribute{
+			"collection": schema.StringAttribute{
+				Required:    true,
+				Description: "The collection of the objects to retrieve.",
+			},
+
+			"api_version": schema.StringAttribute{
+				Optional:    true,
+				Description: "Override the provider Microsoft Graph API version.",
+			},
+
+			"filter": schema.StringAttribute{
+				Optional:    true,
+				Description: "The OData `$filter` expression used to filter the objects.",
+			},
+
+			"select": schema.ListAttribute{
+				Optional:    true,
+				ElementType: types.StringType,
+				Description: "The properties of the objects to retrieve.",
+			},
+
+			"expand": schema.StringAttribute{
+				Optional:    true,
+				Description: "The OData `$expand` expression used to retrieve related objects.",
+			},
+
+			"order_by": schema.ListAttribute{
+				Optional:    true,
+				ElementType: types.StringType,
+				Description: "The OData `$orderby` expressions used to sort the objects, e.g. `displayName desc`.",
+			},
+
+			"search": schema.StringAttribute{
+				Optional:    true,
+				Description: "The OData `$search` expression used to search the objects, e.g. `\"displayName:team\"`.",
+			},
+
+			"top": schema.Int64Attribute{
+				Optional:    true,
+				Description: "The number of objects to retrieve per page.",
+				Validators: []validator.Int64{
+					int64validator.AtLeast(1),
+				},
+			},
+
+			"max_items": schema.Int64Attribute{
+				Optional:    true,
+				Description: "The maximum number of objects to retrieve across all pages, default is all objects.",
+				Validators: []validator.Int64{
+					int64validator.AtLeast(1),
+				},
+			},
+
+			"output": schema.DynamicAttribute{
+				Computed:    true,
+				Description: "The objects retrieved from Microsoft Graph.",
+			},
+		},
+	}
+}
+
+func (r *msGraphObjectsDataSource) Read(ctx context.Context, request datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var model msGraphObjectsDataSourceModel
+	resp.Diagnostics.Append(request.Config.Get(ctx, &model)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	path, diags := ensureIsValidPathString(model.Collection)
+	if diags.HasError() {
+		resp.Diagnostics.Append(diags...)
+		return
+	}
+
+	http := r.client.R(ctx, model.ApiVersion)
+
+	query := odataQuery{
+		Filter:  model.Filter.ValueString(),
+		Select:  listAsStrings(model.Select),
+		Expand:  model.Expand.ValueString(),
+		OrderBy: listAsStrings(model.OrderBy),
+		Search:  model.Search.ValueString(),
+		Top:     model.Top.ValueInt64(),
+	}
+	query.apply(http)
+
+	content, diags := ensureListAsDynamic(http, path, model.MaxItems.ValueInt64())
+	if diags.HasError() {
+		resp.Diagnostics.Append(diags...)
+		return
+	}
+
+	model.Output = content
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, model)...)
+}
@@ -0,0 +1,44 @@
+package msgraph
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+func TestAccMsGraphObjectsDataSource(t *testing.T) {
+	const resourceName = "data.msgraph_objects.groups"
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: protoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: defaultProviderConfigWith(`
+					data "msgraph_objects" "groups" {
+						collection = "groups"
+						select     = ["id", "displayName"]
+						top        = 1
+						max_items  = 2
+					}
+					`),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr(resourceName, "output.#", "2"),
+					resource.TestCheckResourceAttrSet(resourceName, "output.0.id"),
+				),
+			},
+			{
+				Config: defaultProviderConfigWith(`
+					data "msgraph_objects" "groups" {
+						collection = "groups"
+						filter     = "securityEnabled eq true"
+						order_by   = ["displayName"]
+						max_items  = 1
+					}
+					`),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr(resourceName, "output.#", "1"),
+					resource.TestCheckResourceAttr(resourceName, "output.0.securityEnabled", "true"),
+				),
+			},
+		},
+	})
+}
@@ -0,0 +1,134 @@
+package msgraph
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/GoodCloudWorks/terraform-provider-msgraph/msgraph/client"
+	"github.com/GoodCloudWorks/terraform-provider-msgraph/msgraph/id"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// Directory objects such as users, groups and applications are soft-deleted
+// and kept in the deleted items for 30 days, where they can be restored.
+const deletedItemsCollection = "directory/deletedItems"
+
+// The deleted items are eventually consistent, so a just deleted object may
+// only show up there after a while.
+const (
+	purgeTimeout      = 2 * time.Minute
+	purgePollInterval = 5 * time.Second
+)
+
+func deletedItemPath(objectID string) string {
+	return id.New(deletedItemsCollection, objectID).Path
+}
+
+// isSoftDeleted reports whether the object is in the deleted items. Objects that
+// are not directory objects are never found there.
+func isSoftDeleted(ctx context.Context, msGraphClient client.MsGraphClient, apiVersion types.String, objectID string) bool {
+	response, err := get(msGraphClient.R(ctx, apiVersion), deletedItemPath(objectID))
+	return err == nil && response.IsSuccess()
+}
+
+// notFoundDetail explains why the object was not found. The deleted items are
+// only looked up for the collections known to support soft delete.
+func notFoundDetail(ctx context.Context, msGraphClient client.MsGraphClient, apiVersion types.String, id *id.ID) string {
+	if _, ok := deletedItemType(id.Collection()); ok && isSoftDeleted(ctx, msGraphClient, apiVersion, id.ObjectId()) {
+		return fmt.Sprintf("The object %q is soft-deleted and can be restored from %q.", id.Path, deletedItemPath(id.ObjectId()))
+	}
+	return fmt.Sprintf("The object %q does not exist.", id.Path)
+}
+
+// ensurePurgeDeletedItem permanently deletes the soft-deleted object. When
+// `wait` is true the object was just deleted, so it is waited for until it shows
+// up in the deleted items, and a warning is returned if it never does.
+func ensurePurgeDeletedItem(ctx context.Context, msGraphClient client.MsGraphClient, apiVersion types.String, objectID string, wait bool) diag.Diagnostics {
+	deadline := time.Now().Add(purgeTimeout)
+	for {
+		response, err := delete(msGraphClient.R(ctx, apiVersion), deletedItemPath(objectID))
+		if !isNotFound(response, err) {
+			return ensureHttpResponseSucceeded(response, err)
+		}
+
+		if !wait {
+			return noErrors()
+		}
+
+		if time.Now().Add(purgePollInterval).After(deadline) {
+			break
+		}
+
+		// A cancelled context fails the next request.
+		select {
+		case <-time.After(purgePollInterval):
+		case <-ctx.Done():
+		}
+	}
+
+	var diags diag.Diagnostics
+	diags.AddWarning(
+		fmt.Sprintf("Object %q not purged.", objectID),
+		fmt.Sprintf("The object did not show up in %q within %s, so it may still be soft-deleted. Objects that are not directory objects are never soft-deleted.", deletedItemsCollection, purgeTimeout),
+	)
+	return diags
+}
+
+// deletedItemTypes are the types of the soft-deleted objects of the
+// collections that support restoring from the deleted items.
+var deletedItemTypes = map[string]string{
+	"administrativeUnits": "administrativeUnit",
+	"applications":        "application",
+	"groups":              "group",
+	"servicePrincipals":   "servicePrincipal",
+	"users":               "user",
+}
+
+// deletedItemType returns the type of the objects of a collection as found in
+// the deleted items, e.g. `group` for `groups`, or reports that the collection
+// is not known to support soft delete.
+func deletedItemType(collection string) (string, bool) {
+	segments := strings.Split(strings.Trim(collection, "/"), "/")
+	itemType, ok := deletedItemTypes[segments[len(segments)-1]]
+	return itemType, ok
+}
+
+// ensureRestoreDeletedItem restores the single soft-deleted object of type
+// `itemType` matching `filter` and returns its ID, or reports that there is no
+// such object.
+func ensureRestoreDeletedItem(ctx context.Context, msGraphClient client.MsGraphClient, apiVersion types.String, itemType string, filter string) (string, bool, diag.Diagnostics) {
+	collection := deletedItemsCollection + "/microsoft.graph." + itemType
+
+	http := msGraphClient.R(ctx, apiVersion)
+	odataQuery{Filter: filter, Top: 2}.apply(http)
+
+	objectIDs, diags := ensureListObjectIDs(http, collection, 2)
+	if diags.HasError() {
+		return "", false, diags
+	}
+
+	switch len(objectIDs) {
+	case 0:
+		return "", false, noErrors()
+	case 1:
+	default:
+		return "", false, errorDiagnostics(fmt.Sprintf("Multiple soft-deleted objects found in %q.", collection), fmt.Sprintf("More than one object matches the filter: %s", filter))
+	}
+
+	objectID := objectIDs[0]
+
+	// Retrying is only safe as long as the object has not been restored.
+	ctx = client.WithRetryProbe(ctx, func(ctx context.Context) bool {
+		return isSoftDeleted(ctx, msGraphClient, apiVersion, objectID)
+	})
+
+	response, err := post(msGraphClient.R(ctx, apiVersion), deletedItemPath(objectID)+"/restore")
+	if diags := ensureHttpResponseSucceeded(response, err); diags.HasError() {
+		return "", false, diags
+	}
+
+	return objectID, true, noErrors()
+}
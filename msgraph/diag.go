@@ -1,6 +1,11 @@
 package msgraph
 
-import "github.com/hashicorp/terraform-plugin-framework/diag"
+import (
+	"errors"
+
+	"github.com/GoodCloudWorks/terraform-provider-msgraph/msgraph/dynamic"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+)
 
 func noErrors() diag.Diagnostics {
 	return diag.Diagnostics{}
@@ -11,3 +16,16 @@ func errorDiagnostics(summary string, detail string) diag.Diagnostics {
 		diag.NewErrorDiagnostic(summary, detail),
 	}
 }
+
+// dynamicErrorDiagnostics reports an error of the dynamic package, with a
+// dedicated summary for unknown values that would be sent to Microsoft Graph.
+func dynamicErrorDiagnostics(summary string, err error) diag.Diagnostics {
+	var unknown *dynamic.UnknownValueError
+	if errors.As(err, &unknown) {
+		return errorDiagnostics(
+			"Unknown value in request body.",
+			"The "+unknown.Error()+". Unknown values are only known after apply and are never sent to Microsoft Graph, this is a bug in the provider.",
+		)
+	}
+	return errorDiagnostics(summary, err.Error())
+}
@@ -0,0 +1,96 @@
+package dynamic
+
+import (
+	"encoding/json"
+
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// DiffOptions refine how the properties of `target` are compared with
+// `source`.
+type DiffOptions struct {
+	// Removed are the top-level properties that are set to null when
+	// `target` does not contain them.
+	Removed []string
+
+	// Unordered are the paths of the arrays whose order is not significant.
+	Unordered []Path
+
+	// UnorderedPrimitives reports whether the order of the elements of arrays
+	// of primitives is not significant, as for most properties of directory
+	// objects, which Microsoft Graph treats as sets, e.g. `groupTypes` or
+	// `identifierUris`.
+	UnorderedPrimitives bool
+
+	// ArrayKeys are the properties that identify the elements of arrays of
+	// objects, in addition to the default `id`, `keyId` and `resourceAppId`.
+	ArrayKeys []ArrayKey
+
+	// Normalizers are applied in addition to the default normalizers when
+	// comparing values.
+	Normalizers []PathNormalizer
+}
+
+// Diff returns a JSON object with the top-level properties of `target` whose
+// value is not semantically equal to `source`, suitable as the body of a PATCH
+// request. The returned bool reports whether there is any difference.
+func Diff(source, target types.Dynamic, options DiffOptions) ([]byte, bool, error) {
+	sourceObject, err := toJSONObject(source)
+	if err != nil {
+		return nil, false, err
+	}
+
+	targetObject, err := toJSONObject(target)
+	if err != nil {
+		return nil, false, err
+	}
+
+	result := make(map[string]interface{})
+
+	for key, targetValue := range targetObject {
+		sourceValue, ok := sourceObject[key]
+		if !ok || !semanticallyEqualValues(sourceValue, targetValue, Path{key}, arrayOrder{unordered: options.Unordered, keys: options.ArrayKeys, primitives: options.UnorderedPrimitives}, options.Normalizers) {
+			result[key] = targetValue
+		}
+	}
+
+	for _, key := range options.Removed {
+		if _, ok := targetObject[key]; !ok {
+			result[key] = nil
+		}
+	}
+
+	resultJSON, err := json.Marshal(result)
+	if err != nil {
+		return nil, false, err
+	}
+
+	return resultJSON, len(result) > 0, nil
+}
+
+func toJSONObject(value types.Dynamic) (map[string]interface{}, error) {
+	if value.IsNull() {
+		return map[string]interface{}{}, nil
+	}
+
+	value, err := Decode(value)
+	if err != nil {
+		return nil, err
+	}
+
+	valueJSON, err := ToJSON(value)
+	if err != nil {
+		return nil, err
+	}
+
+	var object map[string]interface{}
+	if err := UnmarshalJSON(valueJSON, &object); err != nil {
+		return nil, err
+	}
+
+	if object == nil {
+		object = map[string]interface{}{}
+	}
+
+	return object, nil
+}
@@ -0,0 +1,138 @@
+package dynamic
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDiff(t *testing.T) {
+	tests := []struct {
+		name                string
+		source              string
+		target              string
+		removed             []string
+		unorderedPrimitives bool
+		normalizers         map[string]string
+		expected            string
+		changed             bool
+	}{
+		{
+			name:     "when nothing changed then the diff is empty",
+			source:   `{"displayName":"a","tags":["x"],"info":{"a":1}}`,
+			target:   `{"displayName":"a","tags":["x"],"info":{"a":1}}`,
+			expected: `{}`,
+			changed:  false,
+		},
+		{
+			name:     "when a property changed then only that property is sent",
+			source:   `{"displayName":"a","description":"b"}`,
+			target:   `{"displayName":"c","description":"b"}`,
+			expected: `{"displayName":"c"}`,
+			changed:  true,
+		},
+		{
+			name:     "when a nested property changed then the whole top-level property is sent",
+			source:   `{"info":{"a":1,"b":2}}`,
+			target:   `{"info":{"a":1,"b":3}}`,
+			expected: `{"info":{"a":1,"b":3}}`,
+			changed:  true,
+		},
+		{
+			name:     "when a property was added then it is sent",
+			source:   `{"displayName":"a"}`,
+			target:   `{"displayName":"a","description":"b"}`,
+			expected: `{"description":"b"}`,
+			changed:  true,
+		},
+		{
+			name:     "when a removed property was removed then it is sent as null",
+			source:   `{"displayName":"a","description":"b"}`,
+			target:   `{"displayName":"a"}`,
+			removed:  []string{"description", "displayName"},
+			expected: `{"description":null}`,
+			changed:  true,
+		},
+		{
+			name:     "when a property was removed but not listed as removed then the diff is empty",
+			source:   `{"displayName":"a","description":"b"}`,
+			target:   `{"displayName":"a"}`,
+			expected: `{}`,
+			changed:  false,
+		},
+		{
+			name:                "when an array of primitives was reordered then the diff is empty",
+			source:              `{"groupTypes":["Unified","DynamicMembership"]}`,
+			target:              `{"groupTypes":["DynamicMembership","Unified"]}`,
+			unorderedPrimitives: true,
+			expected:            `{}`,
+			changed:             false,
+		},
+		{
+			name:     "when an array of primitives that are not unordered was reordered then it is sent",
+			source:   `{"groupTypes":["Unified","DynamicMembership"]}`,
+			target:   `{"groupTypes":["DynamicMembership","Unified"]}`,
+			expected: `{"groupTypes":["DynamicMembership","Unified"]}`,
+			changed:  true,
+		},
+		{
+			name:     "when an array of primitives changed then it is sent in the configured order",
+			source:   `{"identifierUris":["api://a"]}`,
+			target:   `{"identifierUris":["api://c","api://a"]}`,
+			expected: `{"identifierUris":["api://c","api://a"]}`,
+			changed:  true,
+		},
+		{
+			name:        "when a property normalized as null only changed from null to an empty array then the diff is empty",
+			source:      `{"tags":null}`,
+			target:      `{"tags":[]}`,
+			normalizers: map[string]string{"/tags": NormalizerEmptyAsNull},
+			expected:    `{}`,
+			changed:     false,
+		},
+		{
+			name:     "when a property changed from null to an empty array then it is sent",
+			source:   `{"tags":null}`,
+			target:   `{"tags":[]}`,
+			expected: `{"tags":[]}`,
+			changed:  true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			source, err := FromJSONImplied([]byte(test.source))
+			require.NoError(t, err)
+
+			target, err := FromJSONImplied([]byte(test.target))
+			require.NoError(t, err)
+
+			options := DiffOptions{Removed: test.removed, UnorderedPrimitives: test.unorderedPrimitives}
+			for pointer, name := range test.normalizers {
+				path, err := ParsePath(pointer)
+				require.NoError(t, err)
+				normalizer, err := ParseNormalizer(name)
+				require.NoError(t, err)
+				options.Normalizers = append(options.Normalizers, PathNormalizer{Path: path, Normalizer: normalizer})
+			}
+
+			actual, changed, err := Diff(source, target, options)
+			require.NoError(t, err)
+			require.JSONEq(t, test.expected, string(actual))
+			require.Equal(t, test.changed, changed)
+		})
+	}
+}
+
+func TestDiffJSONString(t *testing.T) {
+	source := types.DynamicValue(types.StringValue(`{"displayName":"a","description":"b"}`))
+
+	target, err := FromJSONImplied([]byte(`{"displayName":"c","description":"b"}`))
+	require.NoError(t, err)
+
+	actual, changed, err := Diff(source, target, DiffOptions{})
+	require.NoError(t, err)
+	require.True(t, changed)
+	require.JSONEq(t, `{"displayName":"c"}`, string(actual))
+}
@@ -1,23 +1,94 @@
 package dynamic
 
 import (
+	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
+	"io"
 	"math/big"
+	"strconv"
 
 	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/hashicorp/terraform-plugin-framework/types/basetypes"
 )
 
+// numberPrecision is the precision of Terraform numbers, so that integers and
+// decimals round-trip exactly.
+const numberPrecision = 512
+
+// UnmarshalJSON is json.Unmarshal, except that numbers are decoded as
+// json.Number instead of float64 so they do not lose precision.
+func UnmarshalJSON(data []byte, v interface{}) error {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	if err := decoder.Decode(v); err != nil {
+		return err
+	}
+	if _, err := decoder.Token(); err != io.EOF {
+		return fmt.Errorf("invalid character after top-level value in %s", string(data))
+	}
+	return nil
+}
+
+func parseNumber(number json.Number) (*big.Float, error) {
+	f, _, err := big.ParseFloat(number.String(), 10, numberPrecision, big.ToNearestEven)
+	if err != nil {
+		return nil, fmt.Errorf("invalid number %q: %v", number, err)
+	}
+	return f, nil
+}
+
+// formatNumber returns the shortest JSON number that parses to `f`. Integers
+// that are exactly representable are formatted without exponent.
+func formatNumber(f *big.Float) json.Number {
+	if f.IsInt() && f.MantExp(nil) <= numberPrecision {
+		i, _ := f.Int(nil)
+		return json.Number(i.String())
+	}
+	return json.Number(f.Text('g', -1))
+}
+
+// UnknownValueError is returned when an unknown value is converted to JSON.
+// Unknown values only exist at plan time and must never be sent to Microsoft
+// Graph.
+type UnknownValueError struct {
+	Path Path
+}
+
+func (e *UnknownValueError) Error() string {
+	if len(e.Path) == 0 {
+		return "value is unknown"
+	}
+	return fmt.Sprintf("value at %s is unknown", e.Path)
+}
+
+// ToJSON converts `d` to JSON. It returns an *UnknownValueError if `d` is not
+// fully known.
 func ToJSON(d types.Dynamic) ([]byte, error) {
-	return attrValueToJSON(d.UnderlyingValue())
+	encoder := jsonEncoder{}
+	return encoder.attrValueToJSON(d, Path{})
+}
+
+// toJSONAllowUnknown converts `d` to JSON where unknown values are null, and
+// returns the paths of the unknown values.
+func toJSONAllowUnknown(d types.Dynamic) ([]byte, []Path, error) {
+	encoder := jsonEncoder{allowUnknown: true}
+	b, err := encoder.attrValueToJSON(d, Path{})
+	return b, encoder.unknowns, err
 }
 
-func attrListToJSON(in []attr.Value) ([]json.RawMessage, error) {
+type jsonEncoder struct {
+	allowUnknown bool
+	unknowns     []Path
+}
+
+func (e *jsonEncoder) attrListToJSON(in []attr.Value, path Path) ([]json.RawMessage, error) {
 	l := make([]json.RawMessage, 0)
-	for _, v := range in {
-		vv, err := attrValueToJSON(v)
+	for i, v := range in {
+		vv, err := e.attrValueToJSON(v, path.child(strconv.Itoa(i)))
 		if err != nil {
 			return nil, err
 		}
@@ -26,10 +97,10 @@ func attrListToJSON(in []attr.Value) ([]json.RawMessage, error) {
 	return l, nil
 }
 
-func attrMapToJSON(in map[string]attr.Value) (map[string]json.RawMessage, error) {
+func (e *jsonEncoder) attrMapToJSON(in map[string]attr.Value, path Path) (map[string]json.RawMessage, error) {
 	m := map[string]json.RawMessage{}
 	for k, v := range in {
-		vv, err := attrValueToJSON(v)
+		vv, err := e.attrValueToJSON(v, path.child(k))
 		if err != nil {
 			return nil, err
 		}
@@ -38,11 +109,20 @@ func attrMapToJSON(in map[string]attr.Value) (map[string]json.RawMessage, error)
 	return m, nil
 }
 
-func attrValueToJSON(val attr.Value) ([]byte, error) {
-	if val.IsNull() {
+func (e *jsonEncoder) attrValueToJSON(val attr.Value, path Path) ([]byte, error) {
+	if val == nil || val.IsNull() {
+		return json.Marshal(nil)
+	}
+	if val.IsUnknown() {
+		if !e.allowUnknown {
+			return nil, &UnknownValueError{Path: path}
+		}
+		e.unknowns = append(e.unknowns, path)
 		return json.Marshal(nil)
 	}
 	switch value := val.(type) {
+	case types.Dynamic:
+		return e.attrValueToJSON(value.UnderlyingValue(), path)
 	case types.Bool:
 		return json.Marshal(value.ValueBool())
 	case types.String:
@@ -52,34 +132,33 @@ func attrValueToJSON(val attr.Value) ([]byte, error) {
 	case types.Float64:
 		return json.Marshal(value.ValueFloat64())
 	case types.Number:
-		v, _ := value.ValueBigFloat().Float64()
-		return json.Marshal(v)
+		return json.Marshal(formatNumber(value.ValueBigFloat()))
 	case types.List:
-		l, err := attrListToJSON(value.Elements())
+		l, err := e.attrListToJSON(value.Elements(), path)
 		if err != nil {
 			return nil, err
 		}
 		return json.Marshal(l)
 	case types.Set:
-		l, err := attrListToJSON(value.Elements())
+		l, err := e.attrListToJSON(value.Elements(), path)
 		if err != nil {
 			return nil, err
 		}
 		return json.Marshal(l)
 	case types.Tuple:
-		l, err := attrListToJSON(value.Elements())
+		l, err := e.attrListToJSON(value.Elements(), path)
 		if err != nil {
 			return nil, err
 		}
 		return json.Marshal(l)
 	case types.Map:
-		m, err := attrMapToJSON(value.Elements())
+		m, err := e.attrMapToJSON(value.Elements(), path)
 		if err != nil {
 			return nil, err
 		}
 		return json.Marshal(m)
 	case types.Object:
-		m, err := attrMapToJSON(value.Attributes())
+		m, err := e.attrMapToJSON(value.Attributes(), path)
 		if err != nil {
 			return nil, err
 		}
@@ -97,23 +176,55 @@ func FromJSON(b []byte, typ attr.Type) (types.Dynamic, error) {
 	return types.DynamicValue(v), nil
 }
 
-func attrListFromJSON(b []byte, etyp attr.Type) ([]attr.Value, error) {
+// jsonDecoder converts JSON to values of a type.
+type jsonDecoder struct {
+	// preserveType makes values whose JSON does not match the type use the
+	// implied types of FromJSONImplied instead of failing, and keeps the
+	// object attributes that the type does not have.
+	preserveType bool
+}
+
+func attrValueFromJSON(b []byte, typ attr.Type) (attr.Value, error) {
+	return jsonDecoder{}.attrValueFromJSON(b, typ)
+}
+
+// implied returns the value of `b` with its implied type when types are
+// preserved, or else `err`.
+func (d jsonDecoder) implied(b []byte, err error) (attr.Value, error) {
+	if !d.preserveType {
+		return nil, err
+	}
+	_, v, err := attrValueFromJSONImplied(b)
+	return v, err
+}
+
+// attrListFromJSON converts the elements of a JSON array. The returned bool is
+// false when types are preserved and an element does not match `etyp`.
+func (d jsonDecoder) attrListFromJSON(b []byte, etyp attr.Type) ([]attr.Value, bool, error) {
 	var l []json.RawMessage
 	if err := json.Unmarshal(b, &l); err != nil {
-		return nil, err
+		return nil, false, err
 	}
 	vals := make([]attr.Value, 0)
 	for _, b := range l {
-		val, err := attrValueFromJSON(b, etyp)
+		val, err := d.attrValueFromJSON(b, etyp)
 		if err != nil {
-			return nil, err
+			return nil, false, err
+		}
+		if d.preserveType && !val.Type(context.Background()).Equal(etyp) {
+			return nil, false, nil
 		}
 		vals = append(vals, val)
 	}
-	return vals, nil
+	return vals, true, nil
 }
 
-func attrValueFromJSON(b []byte, typ attr.Type) (attr.Value, error) {
+func diagsError(diags diag.Diagnostics) error {
+	diag := diags.Errors()[0]
+	return fmt.Errorf("%s: %s", diag.Summary(), diag.Detail())
+}
+
+func (d jsonDecoder) attrValueFromJSON(b []byte, typ attr.Type) (attr.Value, error) {
 	switch typ := typ.(type) {
 	case basetypes.BoolType:
 		if b == nil || string(b) == "null" {
@@ -121,7 +232,7 @@ func attrValueFromJSON(b []byte, typ attr.Type) (attr.Value, error) {
 		}
 		var v bool
 		if err := json.Unmarshal(b, &v); err != nil {
-			return nil, err
+			return d.implied(b, err)
 		}
 		return types.BoolValue(v), nil
 	case basetypes.StringType:
@@ -130,7 +241,7 @@ func attrValueFromJSON(b []byte, typ attr.Type) (attr.Value, error) {
 		}
 		var v string
 		if err := json.Unmarshal(b, &v); err != nil {
-			return nil, err
+			return d.implied(b, err)
 		}
 		return types.StringValue(v), nil
 	case basetypes.Int64Type:
@@ -139,7 +250,7 @@ func attrValueFromJSON(b []byte, typ attr.Type) (attr.Value, error) {
 		}
 		var v int64
 		if err := json.Unmarshal(b, &v); err != nil {
-			return nil, err
+			return d.implied(b, err)
 		}
 		return types.Int64Value(v), nil
 	case basetypes.Float64Type:
@@ -148,44 +259,50 @@ func attrValueFromJSON(b []byte, typ attr.Type) (attr.Value, error) {
 		}
 		var v float64
 		if err := json.Unmarshal(b, &v); err != nil {
-			return nil, err
+			return d.implied(b, err)
 		}
 		return types.Float64Value(v), nil
 	case basetypes.NumberType:
 		if b == nil || string(b) == "null" {
 			return types.NumberNull(), nil
 		}
-		var v float64
+		var v json.Number
 		if err := json.Unmarshal(b, &v); err != nil {
-			return nil, err
+			return d.implied(b, err)
+		}
+		if d.preserveType && b[0] == '"' {
+			// json.Number also accepts strings that contain a number.
+			return d.implied(b, nil)
 		}
-		return types.NumberValue(big.NewFloat(v)), nil
+		f, err := parseNumber(v)
+		if err != nil {
+			return d.implied(b, err)
+		}
+		return types.NumberValue(f), nil
 	case basetypes.ListType:
 		if b == nil || string(b) == "null" {
 			return types.ListNull(typ.ElemType), nil
 		}
-		vals, err := attrListFromJSON(b, typ.ElemType)
-		if err != nil {
-			return nil, err
+		vals, ok, err := d.attrListFromJSON(b, typ.ElemType)
+		if err != nil || !ok {
+			return d.implied(b, err)
 		}
 		vv, diags := types.ListValue(typ.ElemType, vals)
 		if diags.HasError() {
-			diag := diags.Errors()[0]
-			return nil, fmt.Errorf("%s: %s", diag.Summary(), diag.Detail())
+			return d.implied(b, diagsError(diags))
 		}
 		return vv, nil
 	case basetypes.SetType:
 		if b == nil || string(b) == "null" {
 			return types.SetNull(typ.ElemType), nil
 		}
-		vals, err := attrListFromJSON(b, typ.ElemType)
-		if err != nil {
-			return nil, err
+		vals, ok, err := d.attrListFromJSON(b, typ.ElemType)
+		if err != nil || !ok {
+			return d.implied(b, err)
 		}
 		vv, diags := types.SetValue(typ.ElemType, vals)
 		if diags.HasError() {
-			diag := diags.Errors()[0]
-			return nil, fmt.Errorf("%s: %s", diag.Summary(), diag.Detail())
+			return d.implied(b, diagsError(diags))
 		}
 		return vv, nil
 	case basetypes.TupleType:
@@ -194,23 +311,38 @@ func attrValueFromJSON(b []byte, typ attr.Type) (attr.Value, error) {
 		}
 		var l []json.RawMessage
 		if err := json.Unmarshal(b, &l); err != nil {
-			return nil, err
+			return d.implied(b, err)
 		}
-		if len(l) != len(typ.ElemTypes) {
+		if len(l) != len(typ.ElemTypes) && !d.preserveType {
 			return nil, fmt.Errorf("tuple element size not match: json=%d, type=%d", len(l), len(typ.ElemTypes))
 		}
+		eTypes := make([]attr.Type, 0)
 		vals := make([]attr.Value, 0)
 		for i, b := range l {
-			val, err := attrValueFromJSON(b, typ.ElemTypes[i])
+			if i >= len(typ.ElemTypes) {
+				// New elements use implied types.
+				eType, val, err := attrValueFromJSONImplied(b)
+				if err != nil {
+					return nil, err
+				}
+				eTypes = append(eTypes, eType)
+				vals = append(vals, val)
+				continue
+			}
+			val, err := d.attrValueFromJSON(b, typ.ElemTypes[i])
 			if err != nil {
 				return nil, err
 			}
+			eType := typ.ElemTypes[i]
+			if d.preserveType {
+				eType = val.Type(context.Background())
+			}
+			eTypes = append(eTypes, eType)
 			vals = append(vals, val)
 		}
-		vv, diags := types.TupleValue(typ.ElemTypes, vals)
+		vv, diags := types.TupleValue(eTypes, vals)
 		if diags.HasError() {
-			diag := diags.Errors()[0]
-			return nil, fmt.Errorf("%s: %s", diag.Summary(), diag.Detail())
+			return d.implied(b, diagsError(diags))
 		}
 		return vv, nil
 	case basetypes.MapType:
@@ -219,20 +351,22 @@ func attrValueFromJSON(b []byte, typ attr.Type) (attr.Value, error) {
 		}
 		var m map[string]json.RawMessage
 		if err := json.Unmarshal(b, &m); err != nil {
-			return nil, err
+			return d.implied(b, err)
 		}
 		vals := map[string]attr.Value{}
 		for k, v := range m {
-			val, err := attrValueFromJSON(v, typ.ElemType)
+			val, err := d.attrValueFromJSON(v, typ.ElemType)
 			if err != nil {
 				return nil, err
 			}
+			if d.preserveType && !val.Type(context.Background()).Equal(typ.ElemType) {
+				return d.implied(b, nil)
+			}
 			vals[k] = val
 		}
 		vv, diags := types.MapValue(typ.ElemType, vals)
 		if diags.HasError() {
-			diag := diags.Errors()[0]
-			return nil, fmt.Errorf("%s: %s", diag.Summary(), diag.Detail())
+			return d.implied(b, diagsError(diags))
 		}
 		return vv, nil
 	case basetypes.ObjectType:
@@ -241,22 +375,46 @@ func attrValueFromJSON(b []byte, typ attr.Type) (attr.Value, error) {
 		}
 		var m map[string]json.RawMessage
 		if err := json.Unmarshal(b, &m); err != nil {
-			return nil, err
+			return d.implied(b, err)
 		}
-		vals := map[string]attr.Value{}
 		attrTypes := typ.AttributeTypes()
 
-		for k, attrType := range attrTypes {
-			val, err := attrValueFromJSON(m[k], attrType)
+		// When types are preserved, the attributes are those of the JSON
+		// object rather than those of the type.
+		keys := m
+		if !d.preserveType {
+			keys = map[string]json.RawMessage{}
+			for k := range attrTypes {
+				keys[k] = m[k]
+			}
+		}
+
+		valTypes := map[string]attr.Type{}
+		vals := map[string]attr.Value{}
+		for k, v := range keys {
+			attrType, ok := attrTypes[k]
+			if !ok {
+				// New attributes use implied types.
+				var err error
+				valTypes[k], vals[k], err = attrValueFromJSONImplied(v)
+				if err != nil {
+					return nil, err
+				}
+				continue
+			}
+			val, err := d.attrValueFromJSON(v, attrType)
 			if err != nil {
 				return nil, err
 			}
+			valTypes[k] = attrType
+			if d.preserveType {
+				valTypes[k] = val.Type(context.Background())
+			}
 			vals[k] = val
 		}
-		vv, diags := types.ObjectValue(attrTypes, vals)
+		vv, diags := types.ObjectValue(valTypes, vals)
 		if diags.HasError() {
-			diag := diags.Errors()[0]
-			return nil, fmt.Errorf("%s: %s", diag.Summary(), diag.Detail())
+			return d.implied(b, diagsError(diags))
 		}
 		return vv, nil
 	case basetypes.DynamicType:
@@ -266,6 +424,9 @@ func attrValueFromJSON(b []byte, typ attr.Type) (attr.Value, error) {
 		_, vv, err := attrValueFromJSONImplied(b)
 		return vv, err
 	default:
+		if d.preserveType {
+			return d.implied(b, nil)
+		}
 		return nil, fmt.Errorf("Unhandled type: %T", typ)
 	}
 }
@@ -273,7 +434,7 @@ func attrValueFromJSON(b []byte, typ attr.Type) (attr.Value, error) {
 // FromJSONImplied is similar to FromJSON, while it is for typeless case.
 // In which case, the following type conversion rules are applied (Go -> TF):
 // - bool: bool
-// - float64: number
+// - json.Number: number
 // - string: string
 // - []interface{}: tuple
 // - map[string]interface{}: object
@@ -333,15 +494,19 @@ func attrValueFromJSONImplied(b []byte) (attr.Type, attr.Value, error) {
 
 	// Primitives
 	var v interface{}
-	if err := json.Unmarshal(b, &v); err != nil {
+	if err := UnmarshalJSON(b, &v); err != nil {
 		return nil, nil, fmt.Errorf("failed to unmarshal %s: %v", string(b), err)
 	}
 
 	switch v := v.(type) {
 	case bool:
 		return types.BoolType, types.BoolValue(v), nil
-	case float64:
-		return types.NumberType, types.NumberValue(big.NewFloat(v)), nil
+	case json.Number:
+		f, err := parseNumber(v)
+		if err != nil {
+			return nil, nil, err
+		}
+		return types.NumberType, types.NumberValue(f), nil
 	case string:
 		return types.StringType, types.StringValue(v), nil
 	case nil:
@@ -351,6 +516,58 @@ func attrValueFromJSONImplied(b []byte) (attr.Type, attr.Value, error) {
 	}
 }
 
+// FromJSONPreservingType is similar to FromJSON, while values whose JSON no
+// longer matches `typ`, e.g. new object attributes or a string where a number
+// was, use the implied types of FromJSONImplied. This keeps the types of
+// `tomap()`, `tolist()` and `toset()` values of the configuration.
+func FromJSONPreservingType(b []byte, typ attr.Type) (types.Dynamic, error) {
+	v, err := attrValueFromJSONPreservingType(b, typ)
+	if err != nil {
+		return types.Dynamic{}, err
+	}
+	if d, ok := v.(types.Dynamic); ok {
+		return d, nil
+	}
+	return types.DynamicValue(v), nil
+}
+
+func attrValueFromJSONPreservingType(b []byte, typ attr.Type) (attr.Value, error) {
+	return jsonDecoder{preserveType: true}.attrValueFromJSON(b, typ)
+}
+
+// IsJSONString reports whether `d` is a string, e.g. the result of
+// `jsonencode(...)` or `file("app.json")`, rather than an object.
+func IsJSONString(d types.Dynamic) bool {
+	_, ok := d.UnderlyingValue().(types.String)
+	return ok
+}
+
+// Decode returns the JSON object encoded in `d` when `d` is a known string.
+// Other values are returned as is.
+func Decode(d types.Dynamic) (types.Dynamic, error) {
+	s, ok := d.UnderlyingValue().(types.String)
+	if !ok || s.IsNull() || s.IsUnknown() {
+		return d, nil
+	}
+
+	var object map[string]json.RawMessage
+	if err := json.Unmarshal([]byte(s.ValueString()), &object); err != nil || object == nil {
+		return types.DynamicNull(), fmt.Errorf("invalid JSON object: %s", s.ValueString())
+	}
+
+	return FromJSONImplied([]byte(s.ValueString()))
+}
+
+// Encode returns `d` as a canonical JSON string, with sorted keys and without
+// insignificant whitespace.
+func Encode(d types.Dynamic) (types.Dynamic, error) {
+	b, err := ToJSON(d)
+	if err != nil {
+		return types.DynamicNull(), err
+	}
+	return types.DynamicValue(types.StringValue(string(b))), nil
+}
+
 // IsFullyKnown returns true if `val` is known. If `val` is an aggregate type,
 // IsFullyKnown only returns true if all elements and attributes are known, as
 // well.
@@ -2,6 +2,7 @@ package dynamic
 
 import (
 	"context"
+	"encoding/json"
 	"math/big"
 	"testing"
 
@@ -323,7 +324,7 @@ func TestFromJSON(t *testing.T) {
 						"int64_null":   types.Int64Null(),
 						"float64":      types.Float64Value(1.23),
 						"float64_null": types.Float64Null(),
-						"number":       types.NumberValue(big.NewFloat(1.23)),
+						"number":       mustNumberValue("1.23"),
 						"number_null":  types.NumberNull(),
 						"list": types.ListValueMust(
 							types.BoolType,
@@ -534,11 +535,11 @@ func TestFromJSONImplied(t *testing.T) {
 						"bool_null":    types.DynamicNull(),
 						"string":       types.StringValue("a"),
 						"string_null":  types.DynamicNull(),
-						"int64":        types.NumberValue(big.NewFloat(123)),
+						"int64":        mustNumberValue("123"),
 						"int64_null":   types.DynamicNull(),
-						"float64":      types.NumberValue(big.NewFloat(1.23)),
+						"float64":      mustNumberValue("1.23"),
 						"float64_null": types.DynamicNull(),
-						"number":       types.NumberValue(big.NewFloat(1.23)),
+						"number":       mustNumberValue("1.23"),
 						"number_null":  types.DynamicNull(),
 						"list": types.TupleValueMust(
 							[]attr.Type{
@@ -607,3 +608,207 @@ func TestFromJSONImplied(t *testing.T) {
 		})
 	}
 }
+
+func mustNumberValue(s string) types.Number {
+	f, err := parseNumber(json.Number(s))
+	if err != nil {
+		panic(err)
+	}
+	return types.NumberValue(f)
+}
+
+func TestNumberRoundTrip(t *testing.T) {
+	cases := []struct {
+		input  string
+		expect string
+	}{
+		{input: `9007199254740993`, expect: `9007199254740993`},
+		{input: `-9223372036854775808`, expect: `-9223372036854775808`},
+		{input: `18446744073709551617`, expect: `18446744073709551617`},
+		{input: `123456789012345678901234567890`, expect: `123456789012345678901234567890`},
+		{input: `1.0`, expect: `1`},
+		{input: `0.1`, expect: `0.1`},
+		{input: `3.141592653589793238462643383279`, expect: `3.141592653589793238462643383279`},
+		{input: `1e-7`, expect: `1e-07`},
+		{input: `1.5e+300`, expect: `1.5e+300`},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.input, func(t *testing.T) {
+			implied, err := FromJSONImplied([]byte(`{"value":` + tt.input + `,"values":[` + tt.input + `]}`))
+			require.NoError(t, err)
+
+			actual, err := ToJSON(implied)
+			require.NoError(t, err)
+			require.Equal(t, `{"value":`+tt.expect+`,"values":[`+tt.expect+`]}`, string(actual))
+
+			typed, err := FromJSON([]byte(tt.input), types.NumberType)
+			require.NoError(t, err)
+
+			actual, err = ToJSON(typed)
+			require.NoError(t, err)
+			require.Equal(t, tt.expect, string(actual))
+
+			expected, ok := new(big.Float).SetPrec(numberPrecision).SetString(tt.input)
+			require.True(t, ok)
+			require.Zero(t, typed.UnderlyingValue().(types.Number).ValueBigFloat().Cmp(expected))
+		})
+	}
+}
+
+func TestUpdateWithSchemaPreservationKeepsLargeNumbers(t *testing.T) {
+	source, err := FromJSONImplied([]byte(`{"maxSize":9007199254740993,"quota":18446744073709551617}`))
+	require.NoError(t, err)
+
+	target, err := FromJSONImplied([]byte(`{"maxSize":9007199254740992,"quota":18446744073709551617}`))
+	require.NoError(t, err)
+
+	require.False(t, SemanticallyEqual(source, target))
+
+	actual, err := UpdateWithSchemaPreservation(source, target, UpdateOptions{})
+	require.NoError(t, err)
+
+	actualJSON, err := ToJSON(actual)
+	require.NoError(t, err)
+	require.Equal(t, `{"maxSize":9007199254740993,"quota":18446744073709551617}`, string(actualJSON))
+}
+
+func TestToJSONUnknown(t *testing.T) {
+	input := types.DynamicValue(
+		types.ObjectValueMust(
+			map[string]attr.Type{
+				"displayName": types.StringType,
+				"owners":      types.TupleType{ElemTypes: []attr.Type{types.StringType, types.StringType}},
+			},
+			map[string]attr.Value{
+				"displayName": types.StringValue("a"),
+				"owners": types.TupleValueMust(
+					[]attr.Type{types.StringType, types.StringType},
+					[]attr.Value{types.StringValue("1"), types.StringUnknown()},
+				),
+			},
+		),
+	)
+
+	_, err := ToJSON(input)
+	var unknown *UnknownValueError
+	require.ErrorAs(t, err, &unknown)
+	require.Equal(t, "/owners/1", unknown.Path.String())
+
+	_, err = ToJSON(types.DynamicUnknown())
+	require.ErrorAs(t, err, &unknown)
+
+	b, unknowns, err := toJSONAllowUnknown(input)
+	require.NoError(t, err)
+	require.JSONEq(t, `{"displayName":"a","owners":["1",null]}`, string(b))
+	require.Equal(t, []Path{{"owners", "1"}}, unknowns)
+}
+
+func TestDecode(t *testing.T) {
+	decoded, err := Decode(types.DynamicValue(types.StringValue(`{ "displayName": "a", "tags": ["x"] }`)))
+	require.NoError(t, err)
+
+	actual, err := ToJSON(decoded)
+	require.NoError(t, err)
+	require.Equal(t, `{"displayName":"a","tags":["x"]}`, string(actual))
+
+	object, err := FromJSONImplied([]byte(`{"displayName":"a"}`))
+	require.NoError(t, err)
+
+	unchanged, err := Decode(object)
+	require.NoError(t, err)
+	require.True(t, unchanged.Equal(object))
+
+	_, err = Decode(types.DynamicValue(types.StringValue(`["a"]`)))
+	require.Error(t, err)
+
+	_, err = Decode(types.DynamicValue(types.StringValue(`not json`)))
+	require.Error(t, err)
+}
+
+func TestEncode(t *testing.T) {
+	object, err := FromJSONImplied([]byte(`{ "tags": ["x"], "displayName": "a" }`))
+	require.NoError(t, err)
+
+	encoded, err := Encode(object)
+	require.NoError(t, err)
+	require.True(t, IsJSONString(encoded))
+	require.True(t, encoded.Equal(types.DynamicValue(types.StringValue(`{"displayName":"a","tags":["x"]}`))))
+}
+
+func TestFromJSONPreservingType(t *testing.T) {
+	typ := types.ObjectType{
+		AttrTypes: map[string]attr.Type{
+			"tags":   types.ListType{ElemType: types.StringType},
+			"labels": types.SetType{ElemType: types.StringType},
+			"info":   types.MapType{ElemType: types.StringType},
+			"count":  types.NumberType,
+		},
+	}
+
+	cases := []struct {
+		name   string
+		input  string
+		expect types.Dynamic
+	}{
+		{
+			name:  "when JSON matches the type then the type is kept",
+			input: `{"tags":["a","b"],"labels":["x"],"info":{"k":"v","l":"w"},"count":1}`,
+			expect: types.DynamicValue(types.ObjectValueMust(typ.AttrTypes, map[string]attr.Value{
+				"tags":   types.ListValueMust(types.StringType, []attr.Value{types.StringValue("a"), types.StringValue("b")}),
+				"labels": types.SetValueMust(types.StringType, []attr.Value{types.StringValue("x")}),
+				"info":   types.MapValueMust(types.StringType, map[string]attr.Value{"k": types.StringValue("v"), "l": types.StringValue("w")}),
+				"count":  mustNumberValue("1"),
+			})),
+		},
+		{
+			name:  "when JSON has a new key then it uses the implied type",
+			input: `{"tags":[],"description":"d"}`,
+			expect: types.DynamicValue(types.ObjectValueMust(
+				map[string]attr.Type{
+					"tags":        types.ListType{ElemType: types.StringType},
+					"description": types.StringType,
+				},
+				map[string]attr.Value{
+					"tags":        types.ListValueMust(types.StringType, []attr.Value{}),
+					"description": types.StringValue("d"),
+				},
+			)),
+		},
+		{
+			name:  "when JSON does not match the type then it uses the implied type",
+			input: `{"count":"many"}`,
+			expect: types.DynamicValue(types.ObjectValueMust(
+				map[string]attr.Type{"count": types.StringType},
+				map[string]attr.Value{"count": types.StringValue("many")},
+			)),
+		},
+		{
+			name:  "when JSON has a string containing a number then it uses the implied type",
+			input: `{"count":"5"}`,
+			expect: types.DynamicValue(types.ObjectValueMust(
+				map[string]attr.Type{"count": types.StringType},
+				map[string]attr.Value{"count": types.StringValue("5")},
+			)),
+		},
+		{
+			name:  "when JSON elements do not match the element type then the collection uses the implied type",
+			input: `{"tags":["a",1]}`,
+			expect: types.DynamicValue(types.ObjectValueMust(
+				map[string]attr.Type{"tags": types.TupleType{ElemTypes: []attr.Type{types.StringType, types.NumberType}}},
+				map[string]attr.Value{"tags": types.TupleValueMust(
+					[]attr.Type{types.StringType, types.NumberType},
+					[]attr.Value{types.StringValue("a"), mustNumberValue("1")},
+				)},
+			)),
+		},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			actual, err := FromJSONPreservingType([]byte(tt.input), typ)
+			require.NoError(t, err)
+			require.True(t, tt.expect.Equal(actual), "expected %s, got %s", tt.expect, actual)
+		})
+	}
+}
@@ -0,0 +1,192 @@
+package dynamic
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Normalizer returns the canonical form of a JSON value, so that values that
+// Microsoft Graph treats as equal compare equal.
+type Normalizer func(value interface{}) interface{}
+
+// PathNormalizer applies a normalizer to the values at a path.
+type PathNormalizer struct {
+	Path       Path
+	Normalizer Normalizer
+}
+
+const (
+	NormalizerDateTime        = "datetime"
+	NormalizerGUID            = "guid"
+	NormalizerCaseInsensitive = "case_insensitive"
+	NormalizerSpaceDelimited  = "space_delimited"
+	NormalizerEmptyAsNull     = "empty_as_null"
+)
+
+var normalizers = map[string]Normalizer{
+	NormalizerDateTime:        normalizeDateTime,
+	NormalizerGUID:            normalizeGUID,
+	NormalizerCaseInsensitive: normalizeCaseInsensitive,
+	NormalizerSpaceDelimited:  normalizeSpaceDelimited,
+	NormalizerEmptyAsNull:     normalizeEmptyAsNull,
+}
+
+// defaultNormalizers are applied to every value, as they never make values
+// equal that Microsoft Graph treats as different. Empty values are not
+// normalized by default, as some properties treat `[]` and `null` differently.
+var defaultNormalizers = []Normalizer{
+	normalizeNumber,
+	normalizeDateTime,
+	normalizeGUID,
+}
+
+// NormalizerNames returns the names of the built-in normalizers.
+func NormalizerNames() []string {
+	names := make([]string, 0, len(normalizers))
+	for name := range normalizers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// ParseNormalizer returns the built-in normalizer with the given name.
+func ParseNormalizer(name string) (Normalizer, error) {
+	normalizer, ok := normalizers[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown normalizer %q: must be one of %s", name, strings.Join(NormalizerNames(), ", "))
+	}
+	return normalizer, nil
+}
+
+// normalize returns a copy of `value` where the default normalizers and the
+// `pathNormalizers` matching the path of each nested value are applied.
+func normalize(value interface{}, path Path, pathNormalizers []PathNormalizer) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		result := make(map[string]interface{}, len(v))
+		for key := range v {
+			result[key] = normalize(v[key], path.child(key), pathNormalizers)
+		}
+		value = result
+
+	case []interface{}:
+		result := make([]interface{}, len(v))
+		for i := range v {
+			result[i] = normalize(v[i], path.child(strconv.Itoa(i)), pathNormalizers)
+		}
+		value = result
+	}
+
+	for _, normalizer := range defaultNormalizers {
+		value = normalizer(value)
+	}
+
+	for _, pathNormalizer := range pathNormalizers {
+		if pathNormalizer.Path.Matches(path) {
+			value = pathNormalizer.Normalizer(value)
+		}
+	}
+
+	return value
+}
+
+// semanticallyEqualValues reports whether the JSON values `a` and `b` at
+// `path` are equal after normalization, regardless of the order of unordered
+// and keyed arrays.
+func semanticallyEqualValues(a, b interface{}, path Path, order arrayOrder, pathNormalizers []PathNormalizer) bool {
+	a = canonicalize(normalize(a, path, pathNormalizers), path, order)
+	b = canonicalize(normalize(b, path, pathNormalizers), path, order)
+	return reflect.DeepEqual(a, b)
+}
+
+// normalizeNumber formats numbers in their shortest form, e.g. `1.50` becomes
+// `1.5`.
+func normalizeNumber(value interface{}) interface{} {
+	number, ok := value.(json.Number)
+	if !ok {
+		return value
+	}
+
+	f, err := parseNumber(number)
+	if err != nil {
+		return value
+	}
+
+	return formatNumber(f)
+}
+
+// normalizeDateTime converts RFC 3339 timestamps to UTC without trailing
+// fractional zeros, e.g. `2025-01-01T00:00:00.0000000Z` becomes
+// `2025-01-01T00:00:00Z`.
+func normalizeDateTime(value interface{}) interface{} {
+	s, ok := value.(string)
+	if !ok || !strings.Contains(s, "T") {
+		return value
+	}
+
+	t, err := time.Parse(time.RFC3339Nano, s)
+	if err != nil {
+		return value
+	}
+
+	return t.UTC().Format(time.RFC3339Nano)
+}
+
+var guidRegexp = regexp.MustCompile(`^\{?[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}\}?$`)
+
+// normalizeGUID converts GUIDs to lower case without braces.
+func normalizeGUID(value interface{}) interface{} {
+	s, ok := value.(string)
+	if !ok || !guidRegexp.MatchString(s) {
+		return value
+	}
+
+	return strings.ToLower(strings.Trim(s, "{}"))
+}
+
+// normalizeCaseInsensitive converts strings to lower case, e.g. for `mail`
+// or `userPrincipalName`.
+func normalizeCaseInsensitive(value interface{}) interface{} {
+	s, ok := value.(string)
+	if !ok {
+		return value
+	}
+
+	return strings.ToLower(s)
+}
+
+// normalizeSpaceDelimited sorts the words of strings that are sets of space
+// delimited values, e.g. the `scope` of OAuth2 permission grants.
+func normalizeSpaceDelimited(value interface{}) interface{} {
+	s, ok := value.(string)
+	if !ok {
+		return value
+	}
+
+	words := strings.Fields(s)
+	sort.Strings(words)
+	return strings.Join(words, " ")
+}
+
+// normalizeEmptyAsNull converts empty arrays and objects to null.
+func normalizeEmptyAsNull(value interface{}) interface{} {
+	switch v := value.(type) {
+	case []interface{}:
+		if len(v) == 0 {
+			return nil
+		}
+	case map[string]interface{}:
+		if len(v) == 0 {
+			return nil
+		}
+	}
+
+	return value
+}
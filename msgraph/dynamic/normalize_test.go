@@ -0,0 +1,98 @@
+package dynamic
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestNormalizers(t *testing.T) {
+	tests := []struct {
+		name       string
+		normalizer string
+		value      interface{}
+		expected   interface{}
+	}{
+		{
+			name:       "datetime removes fractional zeros",
+			normalizer: NormalizerDateTime,
+			value:      "2025-01-01T00:00:00.0000000Z",
+			expected:   "2025-01-01T00:00:00Z",
+		},
+		{
+			name:       "datetime converts to UTC",
+			normalizer: NormalizerDateTime,
+			value:      "2025-01-01T02:00:00+02:00",
+			expected:   "2025-01-01T00:00:00Z",
+		},
+		{
+			name:       "datetime keeps other strings",
+			normalizer: NormalizerDateTime,
+			value:      "Team",
+			expected:   "Team",
+		},
+		{
+			name:       "guid converts to lower case",
+			normalizer: NormalizerGUID,
+			value:      "{00000003-0000-0000-C000-000000000000}",
+			expected:   "00000003-0000-0000-c000-000000000000",
+		},
+		{
+			name:       "guid keeps other strings",
+			normalizer: NormalizerGUID,
+			value:      "Team",
+			expected:   "Team",
+		},
+		{
+			name:       "case_insensitive converts to lower case",
+			normalizer: NormalizerCaseInsensitive,
+			value:      "John@Contoso.com",
+			expected:   "john@contoso.com",
+		},
+		{
+			name:       "space_delimited sorts words",
+			normalizer: NormalizerSpaceDelimited,
+			value:      "User.Read  openid email",
+			expected:   "User.Read email openid",
+		},
+		{
+			name:       "empty_as_null converts empty arrays",
+			normalizer: NormalizerEmptyAsNull,
+			value:      []interface{}{},
+			expected:   nil,
+		},
+		{
+			name:       "empty_as_null keeps other arrays",
+			normalizer: NormalizerEmptyAsNull,
+			value:      []interface{}{"a"},
+			expected:   []interface{}{"a"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			normalizer, err := ParseNormalizer(test.normalizer)
+			require.NoError(t, err)
+			require.Equal(t, test.expected, normalizer(test.value))
+		})
+	}
+}
+
+func TestParseNormalizerUnknown(t *testing.T) {
+	_, err := ParseNormalizer("unknown")
+	require.ErrorContains(t, err, "case_insensitive")
+}
+
+func TestNormalizeAtPath(t *testing.T) {
+	path, err := ParsePath("/mail")
+	require.NoError(t, err)
+
+	normalizer, err := ParseNormalizer(NormalizerCaseInsensitive)
+	require.NoError(t, err)
+
+	value := map[string]interface{}{"mail": "A@Contoso.com", "displayName": "A", "tags": []interface{}{}}
+	actual := normalize(value, Path{}, []PathNormalizer{{Path: path, Normalizer: normalizer}})
+
+	require.Equal(t, map[string]interface{}{"mail": "a@contoso.com", "displayName": "A", "tags": []interface{}{}}, actual)
+	require.Equal(t, "A@Contoso.com", value["mail"])
+}
@@ -0,0 +1,142 @@
+package dynamic
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Path is a JSON pointer (RFC 6901) to a value in a JSON document, e.g.
+// `/passwordProfile/password`. The segment `*` matches every array element.
+type Path []string
+
+const pathWildcard = "*"
+
+func ParsePath(pointer string) (Path, error) {
+	if !strings.HasPrefix(pointer, "/") {
+		return nil, fmt.Errorf("invalid JSON pointer %q: must start with \"/\"", pointer)
+	}
+
+	segments := strings.Split(pointer[1:], "/")
+	for i, segment := range segments {
+		segments[i] = strings.ReplaceAll(strings.ReplaceAll(segment, "~1", "/"), "~0", "~")
+	}
+
+	return segments, nil
+}
+
+func (p Path) String() string {
+	var builder strings.Builder
+	for _, segment := range p {
+		builder.WriteString("/")
+		builder.WriteString(strings.ReplaceAll(strings.ReplaceAll(segment, "~", "~0"), "/", "~1"))
+	}
+	return builder.String()
+}
+
+// Matches reports whether the concrete `path` is matched by p, where a
+// wildcard segment of p matches any array index.
+func (p Path) Matches(path Path) bool {
+	if len(p) != len(path) {
+		return false
+	}
+	for i := range p {
+		if p[i] != pathWildcard && p[i] != path[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// overlaps reports whether p matches a prefix of the concrete `path`, or the
+// concrete `path` is a prefix of a path matched by p.
+func (p Path) overlaps(path Path) bool {
+	for i := 0; i < len(p) && i < len(path); i++ {
+		if p[i] != pathWildcard && p[i] != path[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func (p Path) child(segment string) Path {
+	return append(p[:len(p):len(p)], segment)
+}
+
+// copyValue sets the value at p in `target` to the value at p in `source`,
+// wherever `source` has a value at p, and returns the updated `target`.
+// Objects missing from `target` along the way are created.
+func (p Path) copyValue(source, target interface{}) interface{} {
+	if len(p) == 0 {
+		return source
+	}
+
+	segment, rest := p[0], p[1:]
+
+	switch source := source.(type) {
+	case map[string]interface{}:
+		value, ok := source[segment]
+		if !ok {
+			return target
+		}
+
+		targetMap, ok := target.(map[string]interface{})
+		if !ok {
+			targetMap = map[string]interface{}{}
+		}
+		targetMap[segment] = rest.copyValue(value, targetMap[segment])
+		return targetMap
+
+	case []interface{}:
+		targetArray, ok := target.([]interface{})
+		if !ok {
+			return target
+		}
+
+		for i := range source {
+			if i < len(targetArray) && (segment == pathWildcard || segment == strconv.Itoa(i)) {
+				targetArray[i] = rest.copyValue(source[i], targetArray[i])
+			}
+		}
+		return targetArray
+	}
+
+	return target
+}
+
+// get returns the value at p in `value`. When p has wildcards, the values
+// of every array element are returned as an array.
+func (p Path) get(value interface{}) (interface{}, bool) {
+	if len(p) == 0 {
+		return value, true
+	}
+
+	segment, rest := p[0], p[1:]
+
+	switch value := value.(type) {
+	case map[string]interface{}:
+		child, ok := value[segment]
+		if !ok {
+			return nil, false
+		}
+		return rest.get(child)
+
+	case []interface{}:
+		if segment == pathWildcard {
+			values := make([]interface{}, 0, len(value))
+			for _, element := range value {
+				child, _ := rest.get(element)
+				values = append(values, child)
+			}
+			return values, true
+		}
+
+		index, err := strconv.Atoi(segment)
+		if err != nil || index < 0 || index >= len(value) {
+			return nil, false
+		}
+		return rest.get(value[index])
+	}
+
+	return nil, false
+}
@@ -0,0 +1,31 @@
+package dynamic
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParsePath(t *testing.T) {
+	path, err := ParsePath("/owners@odata.bind")
+	require.NoError(t, err)
+	require.Equal(t, Path{"owners@odata.bind"}, path)
+
+	path, err = ParsePath("/a~1b/c~0d/0")
+	require.NoError(t, err)
+	require.Equal(t, Path{"a/b", "c~d", "0"}, path)
+	require.Equal(t, "/a~1b/c~0d/0", path.String())
+
+	_, err = ParsePath("passwordProfile")
+	require.Error(t, err)
+}
+
+func TestPathMatches(t *testing.T) {
+	pattern, err := ParsePath("/keys/*/value")
+	require.NoError(t, err)
+
+	require.True(t, pattern.Matches(Path{"keys", "0", "value"}))
+	require.True(t, pattern.Matches(Path{"keys", "12", "value"}))
+	require.False(t, pattern.Matches(Path{"keys", "0"}))
+	require.False(t, pattern.Matches(Path{"keys", "0", "id"}))
+}
@@ -4,21 +4,65 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"reflect"
 
+	"github.com/hashicorp/terraform-plugin-framework/diag"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 )
 
 type DynamicSemanticallyEqualFunc func(a, b types.Dynamic) bool
 
+// PlanOptions refine how the planned value is compared with the state.
+type PlanOptions struct {
+	// IgnoreChanges are the paths whose changes are treated as equal.
+	IgnoreChanges []Path
+
+	// ReplaceTriggers are the paths whose changes require replacement.
+	ReplaceTriggers []Path
+
+	// Unordered are the paths of the arrays whose order is not significant.
+	Unordered []Path
+
+	// UnorderedPrimitives reports whether the order of the elements of arrays
+	// of primitives is not significant, as for most properties of directory
+	// objects, which Microsoft Graph treats as sets, e.g. `groupTypes` or
+	// `identifierUris`.
+	UnorderedPrimitives bool
+
+	// ArrayKeys are the properties that identify the elements of arrays of
+	// objects, in addition to the default `id`, `keyId` and `resourceAppId`.
+	ArrayKeys []ArrayKey
+
+	// Normalizers are applied in addition to the default normalizers when
+	// comparing values.
+	Normalizers []PathNormalizer
+}
+
+func (options PlanOptions) arrayOrder() arrayOrder {
+	return arrayOrder{unordered: options.Unordered, keys: options.ArrayKeys, primitives: options.UnorderedPrimitives}
+}
+
+// PlanOptionsFunc resolves the plan options of a request, typically from
+// sibling attributes of the configuration.
+type PlanOptionsFunc func(ctx context.Context, request planmodifier.DynamicRequest) (PlanOptions, diag.Diagnostics)
+
 func UseStateWhen(equalFunc DynamicSemanticallyEqualFunc) planmodifier.Dynamic {
 	return dynamicUseStateWhen{
 		EqualFunc: equalFunc,
 	}
 }
 
+func UseStateWhenWithOptions(equalFunc DynamicSemanticallyEqualFunc, optionsFunc PlanOptionsFunc) planmodifier.Dynamic {
+	return dynamicUseStateWhen{
+		EqualFunc:   equalFunc,
+		OptionsFunc: optionsFunc,
+	}
+}
+
 type dynamicUseStateWhen struct {
-	EqualFunc DynamicSemanticallyEqualFunc
+	EqualFunc   DynamicSemanticallyEqualFunc
+	OptionsFunc PlanOptionsFunc
 }
 
 func (u dynamicUseStateWhen) Description(ctx context.Context) string {
@@ -33,32 +77,185 @@ func (u dynamicUseStateWhen) PlanModifyDynamic(ctx context.Context, request plan
 	if request.ConfigValue.IsNull() || request.ConfigValue.IsUnknown() {
 		return
 	}
-	if request.StateValue.IsNull() || request.StateValue.IsUnknown() {
+	if request.StateValue.IsNull() || !IsFullyKnown(request.StateValue) {
 		return
 	}
-	if semanticallyEqual(ctx, request.ConfigValue, request.StateValue) {
+
+	var options PlanOptions
+	if u.OptionsFunc != nil {
+		var diags diag.Diagnostics
+		options, diags = u.OptionsFunc(ctx, request)
+		response.Diagnostics.Append(diags...)
+		if response.Diagnostics.HasError() {
+			return
+		}
+	}
+
+	// A partially unknown value can neither equal the state nor have its
+	// changes ignored, so only replace triggers are evaluated.
+	if !IsFullyKnown(request.ConfigValue) {
+		replace, err := replaceTriggeredUnknown(request.ConfigValue, request.StateValue, options)
+		if err != nil {
+			response.Diagnostics.AddAttributeError(request.Path, "Failed to compare replace triggers.", err.Error())
+			return
+		}
+		response.RequiresReplace = replace
+		return
+	}
+
+	config, err := IgnoreChanges(request.ConfigValue, request.StateValue, options.IgnoreChanges)
+	if err != nil {
+		response.Diagnostics.AddAttributeError(request.Path, "Failed to ignore changes.", err.Error())
+		return
+	}
+
+	if semanticallyEqual(ctx, config, request.StateValue, options) {
 		response.PlanValue = request.StateValue
+		return
+	}
+
+	replace, err := replaceTriggered(config, request.StateValue, options)
+	if err != nil {
+		response.Diagnostics.AddAttributeError(request.Path, "Failed to compare replace triggers.", err.Error())
+		return
+	}
+	response.RequiresReplace = replace
+}
+
+// IgnoreChanges returns `target` with the values at `paths` replaced by the
+// values of `source`.
+func IgnoreChanges(target, source types.Dynamic, paths []Path) (types.Dynamic, error) {
+	if len(paths) == 0 || target.IsNull() || source.IsNull() {
+		return target, nil
+	}
+
+	targetValue, err := toJSONValue(target)
+	if err != nil {
+		return types.DynamicNull(), err
+	}
+
+	sourceValue, err := toJSONValue(source)
+	if err != nil {
+		return types.DynamicNull(), err
+	}
+
+	for _, path := range paths {
+		targetValue = path.copyValue(sourceValue, targetValue)
+	}
+
+	resultJSON, err := json.Marshal(targetValue)
+	if err != nil {
+		return types.DynamicNull(), err
 	}
+
+	return FromJSONImplied(resultJSON)
 }
 
-func semanticallyEqual(ctx context.Context, a, b types.Dynamic) bool {
+func replaceTriggered(config, state types.Dynamic, options PlanOptions) (bool, error) {
+	if len(options.ReplaceTriggers) == 0 {
+		return false, nil
+	}
+
+	configValue, err := toJSONValue(config)
+	if err != nil {
+		return false, err
+	}
+
+	stateValue, err := toJSONValue(state)
+	if err != nil {
+		return false, err
+	}
+
+	order := options.arrayOrder()
+	configValue = canonicalize(normalize(configValue, Path{}, options.Normalizers), Path{}, order)
+	stateValue = canonicalize(normalize(stateValue, Path{}, options.Normalizers), Path{}, order)
+
+	for _, path := range options.ReplaceTriggers {
+		configPathValue, _ := path.get(configValue)
+		statePathValue, _ := path.get(stateValue)
+		if !reflect.DeepEqual(configPathValue, statePathValue) {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+// replaceTriggeredUnknown is replaceTriggered for a partially unknown config,
+// where an unknown value at or within a replace trigger path requires
+// replacement.
+func replaceTriggeredUnknown(config, state types.Dynamic, options PlanOptions) (bool, error) {
+	configJSON, unknowns, err := toJSONAllowUnknown(config)
+	if err != nil {
+		return false, err
+	}
+
+	for _, path := range options.ReplaceTriggers {
+		for _, unknown := range unknowns {
+			if path.overlaps(unknown) {
+				return true, nil
+			}
+		}
+	}
+
+	known, err := FromJSONImplied(configJSON)
+	if err != nil {
+		return false, err
+	}
+
+	return replaceTriggered(known, state, options)
+}
+
+func toJSONValue(value types.Dynamic) (interface{}, error) {
+	value, err := Decode(value)
+	if err != nil {
+		return nil, err
+	}
+
+	valueJSON, err := ToJSON(value)
+	if err != nil {
+		return nil, err
+	}
+
+	var result interface{}
+	if err := UnmarshalJSON(valueJSON, &result); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+func semanticallyEqual(ctx context.Context, a, b types.Dynamic, options PlanOptions) bool {
 	if a.IsNull() && b.IsNull() {
 		return true
 	}
 	if a.IsNull() || b.IsNull() {
 		return false
 	}
-	aType := a.UnderlyingValue().Type(ctx)
-	bType := b.UnderlyingValue().Type(ctx)
-	if aType.Equal(types.StringType) && bType.Equal(types.StringType) {
-		aJson := a.UnderlyingValue().(types.String).ValueString()
-		bJson := b.UnderlyingValue().(types.String).ValueString()
-		return normalizeJson(aJson) == normalizeJson(bJson)
+	if a.Equal(b) {
+		return true
 	}
-	return SemanticallyEqual(a, b)
+	return semanticallyEqualJSON(a, b, options)
 }
 
+// SemanticallyEqual reports whether a and b have the same JSON value after
+// the default normalizers are applied. The order of arrays of primitives is
+// significant.
 func SemanticallyEqual(a, b types.Dynamic) bool {
+	return semanticallyEqualJSON(a, b, PlanOptions{})
+}
+
+// semanticallyEqualJSON compares JSON strings, e.g. `jsonencode(...)`, as the
+// objects they encode, so values are equal across both formats.
+func semanticallyEqualJSON(a, b types.Dynamic, options PlanOptions) bool {
+	a, err := Decode(a)
+	if err != nil {
+		return false
+	}
+	b, err = Decode(b)
+	if err != nil {
+		return false
+	}
 	aJson, err := ToJSON(a)
 	if err != nil {
 		return false
@@ -67,18 +264,81 @@ func SemanticallyEqual(a, b types.Dynamic) bool {
 	if err != nil {
 		return false
 	}
-	return normalizeJson(string(aJson)) == normalizeJson(string(bJson))
+	return normalizeJson(string(aJson), options) == normalizeJson(string(bJson), options)
 }
 
-func normalizeJson(jsonString interface{}) string {
+func normalizeJson(jsonString interface{}, options PlanOptions) string {
 	if jsonString == nil || jsonString == "" {
 		return ""
 	}
 	var j interface{}
 
-	if err := json.Unmarshal([]byte(jsonString.(string)), &j); err != nil {
+	if err := UnmarshalJSON([]byte(jsonString.(string)), &j); err != nil {
 		return fmt.Sprintf("Error parsing JSON: %+v", err)
 	}
-	b, _ := json.Marshal(j)
+	b, _ := json.Marshal(canonicalize(normalize(j, Path{}, options.Normalizers), Path{}, options.arrayOrder()))
 	return string(b)
 }
+
+// KnownWhereConfigured returns a plan modifier that fails the plan when the
+// planned value is unknown where the configuration is known. Terraform only
+// resolves the unknowns of the configuration before apply, so any other
+// unknown would reach the request body sent to Microsoft Graph. It must be the
+// last plan modifier of the attribute.
+func KnownWhereConfigured() planmodifier.Dynamic {
+	return dynamicKnownWhereConfigured{}
+}
+
+type dynamicKnownWhereConfigured struct{}
+
+func (m dynamicKnownWhereConfigured) Description(ctx context.Context) string {
+	return "Require the planned value to be known wherever the configuration is known."
+}
+
+func (m dynamicKnownWhereConfigured) MarkdownDescription(ctx context.Context) string {
+	return m.Description(ctx)
+}
+
+func (m dynamicKnownWhereConfigured) PlanModifyDynamic(ctx context.Context, request planmodifier.DynamicRequest, response *planmodifier.DynamicResponse) {
+	if request.ConfigValue.IsUnknown() || IsFullyKnown(request.PlanValue) {
+		return
+	}
+
+	if request.PlanValue.IsUnknown() {
+		response.Diagnostics.AddAttributeError(request.Path, "Unknown value in request body.", "The planned value is unknown although it is configured, so it would be sent to Microsoft Graph unresolved. This is a bug in the provider.")
+		return
+	}
+
+	_, planUnknowns, err := toJSONAllowUnknown(request.PlanValue)
+	if err != nil {
+		response.Diagnostics.AddAttributeError(request.Path, "Failed to find unknown values.", err.Error())
+		return
+	}
+
+	var configUnknowns []Path
+	if !request.ConfigValue.IsNull() {
+		_, configUnknowns, err = toJSONAllowUnknown(request.ConfigValue)
+		if err != nil {
+			response.Diagnostics.AddAttributeError(request.Path, "Failed to find unknown values.", err.Error())
+			return
+		}
+	}
+
+	for _, planUnknown := range planUnknowns {
+		if !isWithinAny(planUnknown, configUnknowns) {
+			response.Diagnostics.AddAttributeError(request.Path, "Unknown value in request body.", fmt.Sprintf("The planned value at %q is unknown although it is configured, so it would be sent to Microsoft Graph unresolved. This is a bug in the provider.", planUnknown.String()))
+			return
+		}
+	}
+}
+
+// isWithinAny reports whether `path` is one of `paths` or nested within one of
+// them.
+func isWithinAny(path Path, paths []Path) bool {
+	for _, p := range paths {
+		if len(p) <= len(path) && p.overlaps(path) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,330 @@
+package dynamic
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUseStateWhenWithOptions(t *testing.T) {
+	tests := []struct {
+		name                string
+		config              string
+		state               string
+		ignoreChanges       []string
+		replaceTriggers     []string
+		unordered           []string
+		normalizers         map[string]string
+		arrayKeys           map[string]string
+		unorderedPrimitives bool
+		expectState         bool
+		expectReplace       bool
+	}{
+		{
+			name:        "when config equals state then state is used",
+			config:      `{"displayName":"a","web":{"redirectUris":["x"]}}`,
+			state:       `{"displayName":"a","web":{"redirectUris":["x"]}}`,
+			expectState: true,
+		},
+		{
+			name:   "when config differs from state then config is used",
+			config: `{"displayName":"a","web":{"redirectUris":["x"]}}`,
+			state:  `{"displayName":"a","web":{"redirectUris":["y"]}}`,
+		},
+		{
+			name:          "when config differs only at ignored path then state is used",
+			config:        `{"displayName":"a","web":{"redirectUris":["x"]}}`,
+			state:         `{"displayName":"a","web":{"redirectUris":["y"]}}`,
+			ignoreChanges: []string{"/web/redirectUris"},
+			expectState:   true,
+		},
+		{
+			name:          "when config differs at ignored array element path then state is used",
+			config:        `{"keys":[{"id":"1","value":"a"},{"id":"2","value":"b"}]}`,
+			state:         `{"keys":[{"id":"1","value":"c"},{"id":"2","value":"d"}]}`,
+			ignoreChanges: []string{"/keys/*/value"},
+			expectState:   true,
+		},
+		{
+			name:            "when config differs at replace trigger path then replacement is required",
+			config:          `{"displayName":"a","signInAudience":"AzureADMyOrg"}`,
+			state:           `{"displayName":"a","signInAudience":"AzureADMultipleOrgs"}`,
+			replaceTriggers: []string{"/signInAudience"},
+			expectReplace:   true,
+		},
+		{
+			name:            "when config differs outside replace trigger paths then no replacement is required",
+			config:          `{"displayName":"a","signInAudience":"AzureADMyOrg"}`,
+			state:           `{"displayName":"b","signInAudience":"AzureADMyOrg"}`,
+			replaceTriggers: []string{"/signInAudience"},
+		},
+		{
+			name:                "when config reorders an array of primitives then state is used",
+			config:              `{"identifierUris":["api://a","api://b"]}`,
+			state:               `{"identifierUris":["api://b","api://a"]}`,
+			unorderedPrimitives: true,
+			expectState:         true,
+		},
+		{
+			name:   "when config reorders an array of primitives that are not unordered then config is used",
+			config: `{"identifierUris":["api://a","api://b"]}`,
+			state:  `{"identifierUris":["api://b","api://a"]}`,
+		},
+		{
+			name:   "when config reorders an array of objects then config is used",
+			config: `{"scopes":[{"value":"1"},{"value":"2"}]}`,
+			state:  `{"scopes":[{"value":"2"},{"value":"1"}]}`,
+		},
+		{
+			name:        "when config reorders an unordered array of objects then state is used",
+			config:      `{"scopes":[{"value":"1"},{"value":"2"}]}`,
+			state:       `{"scopes":[{"value":"2"},{"value":"1"}]}`,
+			unordered:   []string{"/scopes"},
+			expectState: true,
+		},
+		{
+			name:        "when config reorders an array of objects with a default key then state is used",
+			config:      `{"requiredResourceAccess":[{"resourceAppId":"1"},{"resourceAppId":"2"}]}`,
+			state:       `{"requiredResourceAccess":[{"resourceAppId":"2"},{"resourceAppId":"1"}]}`,
+			expectState: true,
+		},
+		{
+			name:        "when config reorders an array of objects with a configured key then state is used",
+			config:      `{"scopes":[{"value":"1"},{"value":"2"}]}`,
+			state:       `{"scopes":[{"value":"2"},{"value":"1"}]}`,
+			arrayKeys:   map[string]string{"/scopes": "value"},
+			expectState: true,
+		},
+		{
+			name:        "when config differs only by datetime precision then state is used",
+			config:      `{"expirationDateTime":"2025-01-01T00:00:00Z"}`,
+			state:       `{"expirationDateTime":"2025-01-01T00:00:00.0000000Z"}`,
+			expectState: true,
+		},
+		{
+			name:   "when config differs by casing without a normalizer then config is used",
+			config: `{"mail":"A@Contoso.com"}`,
+			state:  `{"mail":"a@contoso.com"}`,
+		},
+		{
+			name:        "when config differs by casing with a case insensitive normalizer then state is used",
+			config:      `{"mail":"A@Contoso.com"}`,
+			state:       `{"mail":"a@contoso.com"}`,
+			normalizers: map[string]string{"/mail": NormalizerCaseInsensitive},
+			expectState: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			config, err := FromJSONImplied([]byte(test.config))
+			require.NoError(t, err)
+
+			state, err := FromJSONImplied([]byte(test.state))
+			require.NoError(t, err)
+
+			options := PlanOptions{UnorderedPrimitives: test.unorderedPrimitives}
+			for _, pointer := range test.ignoreChanges {
+				path, err := ParsePath(pointer)
+				require.NoError(t, err)
+				options.IgnoreChanges = append(options.IgnoreChanges, path)
+			}
+			for _, pointer := range test.replaceTriggers {
+				path, err := ParsePath(pointer)
+				require.NoError(t, err)
+				options.ReplaceTriggers = append(options.ReplaceTriggers, path)
+			}
+			for _, pointer := range test.unordered {
+				path, err := ParsePath(pointer)
+				require.NoError(t, err)
+				options.Unordered = append(options.Unordered, path)
+			}
+			for pointer, name := range test.normalizers {
+				path, err := ParsePath(pointer)
+				require.NoError(t, err)
+				normalizer, err := ParseNormalizer(name)
+				require.NoError(t, err)
+				options.Normalizers = append(options.Normalizers, PathNormalizer{Path: path, Normalizer: normalizer})
+			}
+			for pointer, key := range test.arrayKeys {
+				path, err := ParsePath(pointer)
+				require.NoError(t, err)
+				options.ArrayKeys = append(options.ArrayKeys, ArrayKey{Path: path, Key: key})
+			}
+
+			modifier := UseStateWhenWithOptions(SemanticallyEqual, func(context.Context, planmodifier.DynamicRequest) (PlanOptions, diag.Diagnostics) {
+				return options, nil
+			})
+
+			request := planmodifier.DynamicRequest{
+				ConfigValue: config,
+				PlanValue:   config,
+				StateValue:  state,
+			}
+			response := planmodifier.DynamicResponse{PlanValue: config}
+
+			modifier.PlanModifyDynamic(context.Background(), request, &response)
+
+			require.False(t, response.Diagnostics.HasError())
+			require.Equal(t, test.expectReplace, response.RequiresReplace)
+			if test.expectState {
+				require.True(t, response.PlanValue.Equal(state))
+			} else {
+				require.True(t, response.PlanValue.Equal(config))
+			}
+		})
+	}
+}
+
+func TestIgnoreChanges(t *testing.T) {
+	target, err := FromJSONImplied([]byte(`{"displayName":"a","tags":["x"]}`))
+	require.NoError(t, err)
+
+	source, err := FromJSONImplied([]byte(`{"displayName":"b","tags":["y"]}`))
+	require.NoError(t, err)
+
+	path, err := ParsePath("/tags")
+	require.NoError(t, err)
+
+	actual, err := IgnoreChanges(target, source, []Path{path})
+	require.NoError(t, err)
+
+	actualJSON, err := ToJSON(actual)
+	require.NoError(t, err)
+	require.JSONEq(t, `{"displayName":"a","tags":["y"]}`, string(actualJSON))
+
+	unchanged, err := IgnoreChanges(target, source, nil)
+	require.NoError(t, err)
+	require.True(t, unchanged.Equal(target))
+}
+
+func TestUseStateWhenWithOptionsPartiallyUnknown(t *testing.T) {
+	state, err := FromJSONImplied([]byte(`{"displayName":"a","appId":"1"}`))
+	require.NoError(t, err)
+
+	config := types.DynamicValue(types.ObjectValueMust(
+		map[string]attr.Type{"displayName": types.StringType, "appId": types.StringType},
+		map[string]attr.Value{"displayName": types.StringValue("a"), "appId": types.StringUnknown()},
+	))
+
+	tests := []struct {
+		name            string
+		replaceTriggers []string
+		expectReplace   bool
+	}{
+		{
+			name: "when a nested value is unknown then config is used",
+		},
+		{
+			name:            "when a replace trigger value is unknown then replacement is required",
+			replaceTriggers: []string{"/appId"},
+			expectReplace:   true,
+		},
+		{
+			name:            "when a replace trigger value is known and unchanged then no replacement is required",
+			replaceTriggers: []string{"/displayName"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var options PlanOptions
+			for _, pointer := range test.replaceTriggers {
+				path, err := ParsePath(pointer)
+				require.NoError(t, err)
+				options.ReplaceTriggers = append(options.ReplaceTriggers, path)
+			}
+
+			modifier := UseStateWhenWithOptions(SemanticallyEqual, func(context.Context, planmodifier.DynamicRequest) (PlanOptions, diag.Diagnostics) {
+				return options, nil
+			})
+
+			request := planmodifier.DynamicRequest{
+				ConfigValue: config,
+				PlanValue:   config,
+				StateValue:  state,
+			}
+			response := planmodifier.DynamicResponse{PlanValue: config}
+
+			modifier.PlanModifyDynamic(context.Background(), request, &response)
+
+			require.False(t, response.Diagnostics.HasError())
+			require.Equal(t, test.expectReplace, response.RequiresReplace)
+			require.True(t, response.PlanValue.Equal(config))
+		})
+	}
+}
+
+func TestSemanticallyEqualJSONString(t *testing.T) {
+	object, err := FromJSONImplied([]byte(`{"displayName":"a","tags":["x","y"]}`))
+	require.NoError(t, err)
+
+	require.True(t, SemanticallyEqual(types.DynamicValue(types.StringValue(`{ "tags": ["x","y"], "displayName": "a" }`)), object))
+	require.True(t, SemanticallyEqual(object, types.DynamicValue(types.StringValue(`{"displayName":"a","tags":["x","y"]}`))))
+	require.False(t, SemanticallyEqual(types.DynamicValue(types.StringValue(`{"displayName":"b","tags":["x","y"]}`)), object))
+	require.False(t, SemanticallyEqual(types.DynamicValue(types.StringValue(`{"displayName":"a","tags":["y","x"]}`)), object))
+}
+
+func TestKnownWhereConfigured(t *testing.T) {
+	object := func(displayName, appID attr.Value) types.Dynamic {
+		return types.DynamicValue(types.ObjectValueMust(
+			map[string]attr.Type{"displayName": types.StringType, "appId": types.StringType},
+			map[string]attr.Value{"displayName": displayName, "appId": appID},
+		))
+	}
+
+	tests := []struct {
+		name        string
+		config      types.Dynamic
+		plan        types.Dynamic
+		expectError bool
+	}{
+		{
+			name:   "when the plan is fully known then there is no error",
+			config: object(types.StringValue("a"), types.StringValue("1")),
+			plan:   object(types.StringValue("a"), types.StringValue("1")),
+		},
+		{
+			name:   "when the plan is unknown where the config is unknown then there is no error",
+			config: object(types.StringValue("a"), types.StringUnknown()),
+			plan:   object(types.StringValue("a"), types.StringUnknown()),
+		},
+		{
+			name:   "when the config is unknown then there is no error",
+			config: types.DynamicUnknown(),
+			plan:   types.DynamicUnknown(),
+		},
+		{
+			name:        "when the plan is unknown where the config is known then there is an error",
+			config:      object(types.StringValue("a"), types.StringUnknown()),
+			plan:        object(types.StringUnknown(), types.StringUnknown()),
+			expectError: true,
+		},
+		{
+			name:        "when the plan is unknown although the config is known then there is an error",
+			config:      object(types.StringValue("a"), types.StringValue("1")),
+			plan:        types.DynamicUnknown(),
+			expectError: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			request := planmodifier.DynamicRequest{
+				ConfigValue: test.config,
+				PlanValue:   test.plan,
+			}
+			response := planmodifier.DynamicResponse{PlanValue: test.plan}
+
+			KnownWhereConfigured().PlanModifyDynamic(context.Background(), request, &response)
+
+			require.Equal(t, test.expectError, response.Diagnostics.HasError())
+			require.True(t, response.PlanValue.Equal(test.plan))
+		})
+	}
+}
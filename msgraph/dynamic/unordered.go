@@ -0,0 +1,200 @@
+package dynamic
+
+import (
+	"encoding/json"
+	"reflect"
+	"sort"
+	"strconv"
+)
+
+// ArrayKey identifies the elements of the arrays of objects at Path by the
+// value of their Key property, e.g. `id`.
+type ArrayKey struct {
+	Path Path
+	Key  string
+}
+
+// defaultArrayKeys identify the elements of arrays of objects without a
+// configured key, e.g. `appRoles` by `id`, `keyCredentials` by `keyId` and
+// `requiredResourceAccess` by `resourceAppId`.
+var defaultArrayKeys = []string{"id", "keyId", "resourceAppId"}
+
+// arrayOrder describes the arrays whose order is not significant.
+type arrayOrder struct {
+	unordered  []Path
+	keys       []ArrayKey
+	primitives bool
+}
+
+// key returns the property that identifies the elements of the array at
+// `path`: the configured key, or else the first default key that every
+// element has.
+func (o arrayOrder) key(array []interface{}, path Path) (string, bool) {
+	for _, arrayKey := range o.keys {
+		if arrayKey.Path.Matches(path) {
+			return arrayKey.Key, hasKey(array, arrayKey.Key)
+		}
+	}
+
+	for _, key := range defaultArrayKeys {
+		if hasKey(array, key) {
+			return key, true
+		}
+	}
+
+	return "", false
+}
+
+// hasKey reports whether every element of `array` is an object with a
+// primitive value for `key`.
+func hasKey(array []interface{}, key string) bool {
+	if len(array) == 0 {
+		return false
+	}
+
+	for _, element := range array {
+		object, ok := element.(map[string]interface{})
+		if !ok {
+			return false
+		}
+		switch object[key].(type) {
+		case nil, map[string]interface{}, []interface{}:
+			return false
+		}
+	}
+
+	return true
+}
+
+// isUnordered reports whether the order of the elements of the array at
+// `path` is not significant, either because the path is configured as
+// unordered, because its elements are identified by a key, or because
+// arrays of primitives are unordered and the array only has primitive
+// elements.
+func (o arrayOrder) isUnordered(array []interface{}, path Path) bool {
+	for _, pattern := range o.unordered {
+		if pattern.Matches(path) {
+			return true
+		}
+	}
+
+	if _, ok := o.key(array, path); ok {
+		return true
+	}
+
+	if !o.primitives {
+		return false
+	}
+
+	for _, element := range array {
+		switch element.(type) {
+		case map[string]interface{}, []interface{}:
+			return false
+		}
+	}
+
+	return true
+}
+
+// canonicalize sorts the unordered arrays within `value` in place, so that
+// values that only differ in the order of unordered arrays are equal.
+func canonicalize(value interface{}, path Path, order arrayOrder) interface{} {
+	switch value := value.(type) {
+	case map[string]interface{}:
+		for key := range value {
+			value[key] = canonicalize(value[key], path.child(key), order)
+		}
+
+	case []interface{}:
+		for i := range value {
+			value[i] = canonicalize(value[i], path.child(strconv.Itoa(i)), order)
+		}
+
+		if order.isUnordered(value, path) {
+			keys := make([]string, len(value))
+			for i := range value {
+				key, _ := json.Marshal(value[i])
+				keys[i] = string(key)
+			}
+			sort.Sort(byKeys{keys: keys, values: value})
+		}
+	}
+
+	return value
+}
+
+type byKeys struct {
+	keys   []string
+	values []interface{}
+}
+
+func (b byKeys) Len() int           { return len(b.keys) }
+func (b byKeys) Less(i, j int) bool { return b.keys[i] < b.keys[j] }
+func (b byKeys) Swap(i, j int) {
+	b.keys[i], b.keys[j] = b.keys[j], b.keys[i]
+	b.values[i], b.values[j] = b.values[j], b.values[i]
+}
+
+// reorder returns the elements of `source` in the order of the matching
+// elements of `target`, followed by the elements without a match in their
+// original order. Elements are compared by their `normalize`d values.
+func reorder(source, target []interface{}, normalize func(interface{}) interface{}) []interface{} {
+	result := make([]interface{}, 0, len(source))
+	used := make([]bool, len(source))
+
+	normalizedSource := make([]interface{}, len(source))
+	for i := range source {
+		normalizedSource[i] = normalize(source[i])
+	}
+
+	for _, targetElement := range target {
+		targetElement = normalize(targetElement)
+		for i, sourceElement := range source {
+			if !used[i] && matches(normalizedSource[i], targetElement) {
+				result = append(result, sourceElement)
+				used[i] = true
+				break
+			}
+		}
+	}
+
+	for i, sourceElement := range source {
+		if !used[i] {
+			result = append(result, sourceElement)
+		}
+	}
+
+	return result
+}
+
+// matches reports whether `source` has the values of `target`, ignoring the
+// properties of `source` that `target` does not have.
+func matches(source, target interface{}) bool {
+	switch target := target.(type) {
+	case map[string]interface{}:
+		source, ok := source.(map[string]interface{})
+		if !ok {
+			return false
+		}
+		for key, targetValue := range target {
+			if !matches(source[key], targetValue) {
+				return false
+			}
+		}
+		return true
+
+	case []interface{}:
+		source, ok := source.([]interface{})
+		if !ok || len(source) != len(target) {
+			return false
+		}
+		for i := range target {
+			if !matches(source[i], target[i]) {
+				return false
+			}
+		}
+		return true
+	}
+
+	return reflect.DeepEqual(source, target)
+}
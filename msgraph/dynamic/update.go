@@ -1,16 +1,65 @@
 package dynamic
 
 import (
+	"context"
 	"encoding/json"
+	"reflect"
+	"strconv"
+	"strings"
 
 	"github.com/hashicorp/terraform-plugin-framework/types"
 )
 
-func UpdateWithSchemaPreservation(source, target types.Dynamic) (types.Dynamic, error) {
+const odataBindSuffix = "@odata.bind"
+
+type UpdateOptions struct {
+	// WriteOnly are the paths of the properties that Microsoft Graph never
+	// returns, e.g. `/passwordProfile`, whose value in target is kept.
+	WriteOnly []Path
+
+	// Unordered are the paths of the arrays whose order is not significant.
+	Unordered []Path
+
+	// UnorderedPrimitives reports whether the order of the elements of arrays
+	// of primitives is not significant, as for most properties of directory
+	// objects, which Microsoft Graph treats as sets, e.g. `groupTypes` or
+	// `identifierUris`.
+	UnorderedPrimitives bool
+
+	// ArrayKeys are the properties that identify the elements of arrays of
+	// objects, in addition to the default `id`, `keyId` and `resourceAppId`.
+	ArrayKeys []ArrayKey
+
+	// Normalizers are applied in addition to the default normalizers when
+	// comparing values.
+	Normalizers []PathNormalizer
+}
+
+// UpdateWithSchemaPreservation updates the values of `target` with the values
+// of `source`, keeping the structure of `target`. Properties missing from
+// `source` are dropped, except write-only properties and `@odata.bind` links.
+// Values of `target` that are semantically equal to `source` are kept as is.
+// A JSON string `target` is updated as the object it encodes and returned as a
+// canonical JSON string.
+func UpdateWithSchemaPreservation(source, target types.Dynamic, options UpdateOptions) (types.Dynamic, error) {
 	if source.IsNull() || target.IsNull() {
 		return target, nil
 	}
 
+	if IsJSONString(target) {
+		decoded, err := Decode(target)
+		if err != nil {
+			return types.DynamicNull(), err
+		}
+
+		result, err := UpdateWithSchemaPreservation(source, decoded, options)
+		if err != nil {
+			return types.DynamicNull(), err
+		}
+
+		return Encode(result)
+	}
+
 	sourceJSON, err := ToJSON(source)
 	if err != nil {
 		return types.DynamicNull(), err
@@ -22,65 +71,125 @@ func UpdateWithSchemaPreservation(source, target types.Dynamic) (types.Dynamic,
 	}
 
 	var sourceObject interface{}
-	if err := json.Unmarshal(sourceJSON, &sourceObject); err != nil {
+	if err := UnmarshalJSON(sourceJSON, &sourceObject); err != nil {
 		return types.DynamicNull(), err
 	}
 
 	var targetObject interface{}
-	if err := json.Unmarshal(targetJSON, &targetObject); err != nil {
+	if err := UnmarshalJSON(targetJSON, &targetObject); err != nil {
 		return types.DynamicNull(), err
 	}
 
-	resultObject := updateObjects(sourceObject, targetObject)
+	resultObject := options.updateObjects(sourceObject, targetObject, Path{})
+	for _, path := range options.WriteOnly {
+		resultObject = path.copyValue(targetObject, resultObject)
+	}
 
 	resultJSON, err := json.Marshal(resultObject)
 	if err != nil {
 		return types.DynamicNull(), err
 	}
 
-	return FromJSONImplied(resultJSON)
+	// The refreshed value keeps the type of `target`, e.g. a map instead of an
+	// object, so that it does not change type between plan and state.
+	return FromJSONPreservingType(resultJSON, target.UnderlyingValue().Type(context.Background()))
 }
 
-func updateObjects(source, target interface{}) interface{} {
+func (options UpdateOptions) updateObjects(source, target interface{}, path Path) interface{} {
+	order := arrayOrder{unordered: options.Unordered, keys: options.ArrayKeys, primitives: options.UnorderedPrimitives}
+	if semanticallyEqualValues(source, target, path, order, options.Normalizers) {
+		return target
+	}
+
 	switch target := target.(type) {
 	case map[string]interface{}:
-		source, ok := source.(map[string]interface{})
+		sourceMap, ok := source.(map[string]interface{})
 		if !ok {
 			return source
 		}
 
-		return updateMap(source, target)
+		return options.updateMap(sourceMap, target, path)
 
 	case []interface{}:
-		source, ok := source.([]interface{})
+		sourceArray, ok := source.([]interface{})
 		if !ok {
-			return target
+			return source
+		}
+
+		if key, ok := order.key(target, path); ok {
+			return options.updateKeyedArray(sourceArray, target, path, key)
+		}
+
+		if order.isUnordered(target, path) {
+			elementPath := path.child(pathWildcard)
+			sourceArray = reorder(sourceArray, target, func(value interface{}) interface{} {
+				return normalize(value, elementPath, options.Normalizers)
+			})
 		}
 
-		return updateArray(source, target)
+		return options.updateArray(sourceArray, target, path)
 	}
 
-	return target
+	return source
 }
 
-func updateMap(source, target map[string]interface{}) map[string]interface{} {
+func (options UpdateOptions) updateMap(source, target map[string]interface{}, path Path) map[string]interface{} {
 	result := make(map[string]interface{})
 
 	for key := range target {
 		if sourceValue, ok := source[key]; ok {
-			result[key] = updateObjects(sourceValue, target[key])
+			result[key] = options.updateObjects(sourceValue, target[key], path.child(key))
+		} else if strings.HasSuffix(key, odataBindSuffix) {
+			// Links are never returned by Microsoft Graph.
+			result[key] = target[key]
+		}
+	}
+
+	return result
+}
+
+// updateKeyedArray updates the elements of `target` with the elements of
+// `source` that have the same `key` value. Elements of `target` without a
+// match are dropped, and elements of `source` without a match are appended.
+func (options UpdateOptions) updateKeyedArray(source, target []interface{}, path Path, key string) []interface{} {
+	result := make([]interface{}, 0, len(source))
+	used := make([]bool, len(source))
+	keyPath := path.child(pathWildcard).child(key)
+
+	keyOf := func(element interface{}) interface{} {
+		object, ok := element.(map[string]interface{})
+		if !ok {
+			return nil
+		}
+		return normalize(object[key], keyPath, options.Normalizers)
+	}
+
+	for i, targetElement := range target {
+		targetKey := keyOf(targetElement)
+		for j, sourceElement := range source {
+			if !used[j] && targetKey != nil && reflect.DeepEqual(keyOf(sourceElement), targetKey) {
+				used[j] = true
+				result = append(result, options.updateObjects(sourceElement, targetElement, path.child(strconv.Itoa(i))))
+				break
+			}
+		}
+	}
+
+	for j, sourceElement := range source {
+		if !used[j] {
+			result = append(result, sourceElement)
 		}
 	}
 
 	return result
 }
 
-func updateArray(source, target []interface{}) []interface{} {
+func (options UpdateOptions) updateArray(source, target []interface{}, path Path) []interface{} {
 	result := make([]interface{}, 0, len(source))
 
 	for i := range target {
 		if i < len(source) {
-			result = append(result, updateObjects(source[i], target[i]))
+			result = append(result, options.updateObjects(source[i], target[i], path.child(strconv.Itoa(i))))
 		}
 	}
 
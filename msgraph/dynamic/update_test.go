@@ -0,0 +1,224 @@
+package dynamic
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUpdateWithSchemaPreservation(t *testing.T) {
+	tests := []struct {
+		name                string
+		source              string
+		target              string
+		writeOnly           []string
+		unordered           []string
+		normalizers         map[string]string
+		arrayKeys           map[string]string
+		unorderedPrimitives bool
+		expected            string
+	}{
+		{
+			name:     "when source has extra properties then they are dropped",
+			source:   `{"displayName":"a","id":"1"}`,
+			target:   `{"displayName":"a"}`,
+			expected: `{"displayName":"a"}`,
+		},
+		{
+			name:     "when source misses a property then it is dropped",
+			source:   `{"displayName":"a"}`,
+			target:   `{"displayName":"a","passwordProfile":{"password":"secret"}}`,
+			expected: `{"displayName":"a"}`,
+		},
+		{
+			name:      "when a write-only property is missing from source then it is kept",
+			source:    `{"displayName":"a"}`,
+			target:    `{"displayName":"a","passwordProfile":{"password":"secret"}}`,
+			writeOnly: []string{"/passwordProfile"},
+			expected:  `{"displayName":"a","passwordProfile":{"password":"secret"}}`,
+		},
+		{
+			name:      "when a nested write-only property is missing from source then it is kept",
+			source:    `{"passwordProfile":{"forceChangePasswordNextSignIn":true}}`,
+			target:    `{"passwordProfile":{"forceChangePasswordNextSignIn":true,"password":"secret"}}`,
+			writeOnly: []string{"/passwordProfile/password"},
+			expected:  `{"passwordProfile":{"forceChangePasswordNextSignIn":true,"password":"secret"}}`,
+		},
+		{
+			name:      "when a write-only property is in an array then it is kept for every element",
+			source:    `{"keys":[{"keyId":"1"},{"keyId":"2"}]}`,
+			target:    `{"keys":[{"keyId":"1","secret":"a"},{"keyId":"2","secret":"b"}]}`,
+			writeOnly: []string{"/keys/*/secret"},
+			expected:  `{"keys":[{"keyId":"1","secret":"a"},{"keyId":"2","secret":"b"}]}`,
+		},
+		{
+			name:     "when an odata bind link is missing from source then it is kept",
+			source:   `{"displayName":"a"}`,
+			target:   `{"displayName":"a","owners@odata.bind":["https://graph.microsoft.com/v1.0/users/1"]}`,
+			expected: `{"displayName":"a","owners@odata.bind":["https://graph.microsoft.com/v1.0/users/1"]}`,
+		},
+		{
+			name:                "when source reorders an array of primitives then the target order is kept",
+			source:              `{"groupTypes":["Unified","DynamicMembership"]}`,
+			target:              `{"groupTypes":["DynamicMembership","Unified"]}`,
+			unorderedPrimitives: true,
+			expected:            `{"groupTypes":["DynamicMembership","Unified"]}`,
+		},
+		{
+			name:     "when source reorders an array of primitives that are not unordered then the source order is used",
+			source:   `{"groupTypes":["Unified","DynamicMembership"]}`,
+			target:   `{"groupTypes":["DynamicMembership","Unified"]}`,
+			expected: `{"groupTypes":["Unified","DynamicMembership"]}`,
+		},
+		{
+			name:      "when source reorders an unordered array of objects then elements are matched by value",
+			source:    `{"scopes":[{"id":"2","value":"b","isEnabled":true},{"id":"1","value":"a","isEnabled":true}]}`,
+			target:    `{"scopes":[{"id":"1","value":"a"},{"id":"2","value":"b"}]}`,
+			unordered: []string{"/scopes"},
+			expected:  `{"scopes":[{"id":"1","value":"a"},{"id":"2","value":"b"}]}`,
+		},
+		{
+			name:      "when source has an extra element in an unordered array then it is appended",
+			source:    `{"scopes":[{"id":"3"},{"id":"1"}]}`,
+			target:    `{"scopes":[{"id":"1"}]}`,
+			unordered: []string{"/scopes"},
+			expected:  `{"scopes":[{"id":"1"},{"id":"3"}]}`,
+		},
+		{
+			name:     "when source has a different primitive value then the source value is used",
+			source:   `{"displayName":"b"}`,
+			target:   `{"displayName":"a"}`,
+			expected: `{"displayName":"b"}`,
+		},
+		{
+			name:     "when source has a semantically equal primitive value then the target value is kept",
+			source:   `{"createdDateTime":"2025-01-01T00:00:00.0000000Z","appId":"00000003-0000-0000-C000-000000000000"}`,
+			target:   `{"createdDateTime":"2025-01-01T00:00:00Z","appId":"00000003-0000-0000-c000-000000000000"}`,
+			expected: `{"createdDateTime":"2025-01-01T00:00:00Z","appId":"00000003-0000-0000-c000-000000000000"}`,
+		},
+		{
+			name:        "when source has null for an empty array normalized as null then the target value is kept",
+			source:      `{"tags":null}`,
+			target:      `{"tags":[]}`,
+			normalizers: map[string]string{"/tags": NormalizerEmptyAsNull},
+			expected:    `{"tags":[]}`,
+		},
+		{
+			name:     "when source has null for an empty array then the source value is used",
+			source:   `{"tags":null}`,
+			target:   `{"tags":[]}`,
+			expected: `{"tags":null}`,
+		},
+		{
+			name:        "when source matches target with a path normalizer then the target value is kept",
+			source:      `{"mail":"a@contoso.com","scope":"openid User.Read"}`,
+			target:      `{"mail":"A@Contoso.com","scope":"User.Read openid"}`,
+			normalizers: map[string]string{"/mail": NormalizerCaseInsensitive, "/scope": NormalizerSpaceDelimited},
+			expected:    `{"mail":"A@Contoso.com","scope":"User.Read openid"}`,
+		},
+		{
+			name:     "when source reorders an array of objects with a default key then elements are matched by key",
+			source:   `{"appRoles":[{"id":"2","value":"b","isEnabled":false},{"id":"1","value":"a","isEnabled":true}]}`,
+			target:   `{"appRoles":[{"id":"1","value":"a","isEnabled":true},{"id":"2","value":"b","isEnabled":true}]}`,
+			expected: `{"appRoles":[{"id":"1","value":"a","isEnabled":true},{"id":"2","value":"b","isEnabled":false}]}`,
+		},
+		{
+			name:     "when source misses an element with a default key then it is dropped",
+			source:   `{"requiredResourceAccess":[{"resourceAppId":"2","resourceAccess":[]}]}`,
+			target:   `{"requiredResourceAccess":[{"resourceAppId":"1","resourceAccess":[]},{"resourceAppId":"2","resourceAccess":[]}]}`,
+			expected: `{"requiredResourceAccess":[{"resourceAppId":"2","resourceAccess":[]}]}`,
+		},
+		{
+			name:      "when source reorders an array of objects with a configured key then elements are matched by key",
+			source:    `{"scopes":[{"value":"b","isEnabled":false,"type":"User"},{"value":"a","isEnabled":true,"type":"User"}]}`,
+			target:    `{"scopes":[{"value":"a","isEnabled":true},{"value":"b","isEnabled":true},{"value":"c","isEnabled":true}]}`,
+			arrayKeys: map[string]string{"/scopes": "value"},
+			expected:  `{"scopes":[{"value":"a","isEnabled":true},{"value":"b","isEnabled":false}]}`,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			source, err := FromJSONImplied([]byte(test.source))
+			require.NoError(t, err)
+
+			target, err := FromJSONImplied([]byte(test.target))
+			require.NoError(t, err)
+
+			options := UpdateOptions{UnorderedPrimitives: test.unorderedPrimitives}
+			for _, pointer := range test.writeOnly {
+				path, err := ParsePath(pointer)
+				require.NoError(t, err)
+				options.WriteOnly = append(options.WriteOnly, path)
+			}
+			for _, pointer := range test.unordered {
+				path, err := ParsePath(pointer)
+				require.NoError(t, err)
+				options.Unordered = append(options.Unordered, path)
+			}
+			for pointer, name := range test.normalizers {
+				path, err := ParsePath(pointer)
+				require.NoError(t, err)
+				normalizer, err := ParseNormalizer(name)
+				require.NoError(t, err)
+				options.Normalizers = append(options.Normalizers, PathNormalizer{Path: path, Normalizer: normalizer})
+			}
+			for pointer, key := range test.arrayKeys {
+				path, err := ParsePath(pointer)
+				require.NoError(t, err)
+				options.ArrayKeys = append(options.ArrayKeys, ArrayKey{Path: path, Key: key})
+			}
+
+			actual, err := UpdateWithSchemaPreservation(source, target, options)
+			require.NoError(t, err)
+
+			actualJSON, err := ToJSON(actual)
+			require.NoError(t, err)
+			require.JSONEq(t, test.expected, string(actualJSON))
+		})
+	}
+}
+
+func TestUpdateWithSchemaPreservationJSONString(t *testing.T) {
+	source, err := FromJSONImplied([]byte(`{"displayName":"b","id":"1","tags":["x"]}`))
+	require.NoError(t, err)
+
+	target := types.DynamicValue(types.StringValue(`{ "tags": ["x"], "displayName": "a" }`))
+
+	actual, err := UpdateWithSchemaPreservation(source, target, UpdateOptions{})
+	require.NoError(t, err)
+	require.True(t, actual.Equal(types.DynamicValue(types.StringValue(`{"displayName":"b","tags":["x"]}`))))
+}
+
+func TestUpdateWithSchemaPreservationKeepsType(t *testing.T) {
+	source, err := FromJSONImplied([]byte(`{"identifierUris":["api://b"],"tags":{"team":"b"},"id":"1"}`))
+	require.NoError(t, err)
+
+	target := types.DynamicValue(types.ObjectValueMust(
+		map[string]attr.Type{
+			"identifierUris": types.ListType{ElemType: types.StringType},
+			"tags":           types.MapType{ElemType: types.StringType},
+		},
+		map[string]attr.Value{
+			"identifierUris": types.ListValueMust(types.StringType, []attr.Value{types.StringValue("api://a")}),
+			"tags":           types.MapValueMust(types.StringType, map[string]attr.Value{"team": types.StringValue("a")}),
+		},
+	))
+
+	actual, err := UpdateWithSchemaPreservation(source, target, UpdateOptions{})
+	require.NoError(t, err)
+
+	expected := types.DynamicValue(types.ObjectValueMust(
+		map[string]attr.Type{
+			"identifierUris": types.ListType{ElemType: types.StringType},
+			"tags":           types.MapType{ElemType: types.StringType},
+		},
+		map[string]attr.Value{
+			"identifierUris": types.ListValueMust(types.StringType, []attr.Value{types.StringValue("api://b")}),
+			"tags":           types.MapValueMust(types.StringType, map[string]attr.Value{"team": types.StringValue("b")}),
+		},
+	))
+	require.True(t, expected.Equal(actual), "expected %s, got %s", expected, actual)
+}
@@ -0,0 +1,125 @@
+package msgraph
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/go-resty/resty/v2"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+)
+
+const headerClientRequestID = "client-request-id"
+
+type graphError struct {
+	Code       string `json:"code"`
+	Message    string `json:"message"`
+	InnerError struct {
+		RequestID       string `json:"request-id"`
+		ClientRequestID string `json:"client-request-id"`
+		Date            string `json:"date"`
+	} `json:"innerError"`
+	Details []struct {
+		Code    string `json:"code"`
+		Message string `json:"message"`
+		Target  string `json:"target"`
+	} `json:"details"`
+}
+
+// parseGraphError decodes the Microsoft Graph error envelope of a response.
+// The request IDs fall back to the response headers when the envelope has none.
+func parseGraphError(response *resty.Response) (*graphError, bool) {
+	var content struct {
+		Error *graphError `json:"error"`
+	}
+	if err := json.Unmarshal(response.Body(), &content); err != nil || content.Error == nil || content.Error.Code == "" {
+		return nil, false
+	}
+
+	graphError := content.Error
+	if graphError.InnerError.RequestID == "" {
+		graphError.InnerError.RequestID = response.Header().Get("request-id")
+	}
+	if graphError.InnerError.ClientRequestID == "" {
+		graphError.InnerError.ClientRequestID = response.Request.Header.Get(headerClientRequestID)
+	}
+
+	return graphError, true
+}
+
+func (e *graphError) detail() string {
+	var detail strings.Builder
+	fmt.Fprintf(&detail, "%s: %s", e.Code, e.Message)
+
+	for _, d := range e.Details {
+		if d.Target != "" {
+			fmt.Fprintf(&detail, "\n- %s (%s): %s", d.Code, d.Target, d.Message)
+		} else {
+			fmt.Fprintf(&detail, "\n- %s: %s", d.Code, d.Message)
+		}
+	}
+
+	detail.WriteString("\n")
+	if e.InnerError.RequestID != "" {
+		fmt.Fprintf(&detail, "\nRequest ID: %s", e.InnerError.RequestID)
+	}
+	if e.InnerError.ClientRequestID != "" {
+		fmt.Fprintf(&detail, "\nClient request ID: %s", e.InnerError.ClientRequestID)
+	}
+	if e.InnerError.Date != "" {
+		fmt.Fprintf(&detail, "\nDate: %s", e.InnerError.Date)
+	}
+
+	return strings.TrimSpace(detail.String())
+}
+
+// isPropertyValidationError reports whether the error was caused by an invalid
+// property in the request body, rather than by the request itself.
+func (e *graphError) isPropertyValidationError() bool {
+	for _, d := range e.Details {
+		if d.Target != "" {
+			return true
+		}
+	}
+
+	message := strings.ToLower(e.Message)
+	switch e.Code {
+	case "Request_BadRequest", "BadRequest", "invalidRequest", "Request_InvalidValue", "Request_PropertyValueInvalid":
+		return strings.Contains(message, "property") || strings.Contains(message, "value")
+	}
+
+	return false
+}
+
+// isNotFound reports whether the request failed because the object does not exist.
+func isNotFound(response *resty.Response, err error) bool {
+	if err != nil || response == nil || !response.IsError() {
+		return false
+	}
+
+	if response.StatusCode() == httpStatusNotFound {
+		return true
+	}
+
+	graphError, ok := parseGraphError(response)
+	return ok && graphError.Code == "Request_ResourceNotFound"
+}
+
+// ensurePropertiesResponseSucceeded is ensureHttpResponseSucceeded for requests
+// with the `properties` attribute as body, so that property validation errors
+// are attached to that attribute.
+func ensurePropertiesResponseSucceeded(response *resty.Response, err error) diag.Diagnostics {
+	diags := ensureHttpResponseSucceeded(response, err)
+	if !diags.HasError() || err != nil {
+		return diags
+	}
+
+	if graphError, ok := parseGraphError(response); ok && graphError.isPropertyValidationError() {
+		return diag.Diagnostics{
+			diag.NewAttributeErrorDiagnostic(path.Root("properties"), diags[0].Summary(), diags[0].Detail()),
+		}
+	}
+
+	return diags
+}
@@ -0,0 +1,117 @@
+package msgraph
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/go-resty/resty/v2"
+	"github.com/stretchr/testify/require"
+)
+
+func testResponse(statusCode int, body string) *resty.Response {
+	request := &resty.Request{Header: http.Header{}}
+	request.Header.Set(headerClientRequestID, "client-1")
+
+	response := &resty.Response{
+		Request: request,
+		RawResponse: &http.Response{
+			StatusCode: statusCode,
+			Header:     http.Header{"Request-Id": []string{"request-1"}},
+		},
+	}
+	return response.SetBody([]byte(body))
+}
+
+func TestParseGraphError(t *testing.T) {
+	tests := []struct {
+		name     string
+		body     string
+		ok       bool
+		expected string
+	}{
+		{
+			name: "when the envelope has an inner error then its request IDs are used",
+			body: `{"error":{"code":"Request_BadRequest","message":"Invalid value.","innerError":{"request-id":"request-2","client-request-id":"client-2","date":"2025-01-01T00:00:00"}}}`,
+			ok:   true,
+			expected: "Request_BadRequest: Invalid value.\n\n" +
+				"Request ID: request-2\nClient request ID: client-2\nDate: 2025-01-01T00:00:00",
+		},
+		{
+			name:     "when the envelope has no inner error then the request IDs fall back to the headers",
+			body:     `{"error":{"code":"Request_ResourceNotFound","message":"Resource does not exist."}}`,
+			ok:       true,
+			expected: "Request_ResourceNotFound: Resource does not exist.\n\nRequest ID: request-1\nClient request ID: client-1",
+		},
+		{
+			name: "when the envelope has details then they are listed with their target",
+			body: `{"error":{"code":"BadRequest","message":"Invalid request.","details":[{"code":"InvalidValue","message":"Invalid mail.","target":"mail"},{"code":"Other","message":"Other error."}]}}`,
+			ok:   true,
+			expected: "BadRequest: Invalid request.\n- InvalidValue (mail): Invalid mail.\n- Other: Other error.\n\n" +
+				"Request ID: request-1\nClient request ID: client-1",
+		},
+		{
+			name: "when the body is not JSON then it is not a Graph error",
+			body: `<html>Bad Gateway</html>`,
+			ok:   false,
+		},
+		{
+			name: "when the body has no error code then it is not a Graph error",
+			body: `{"error":{"message":"Something failed."}}`,
+			ok:   false,
+		},
+		{
+			name: "when the body is empty then it is not a Graph error",
+			body: ``,
+			ok:   false,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			graphError, ok := parseGraphError(testResponse(http.StatusBadRequest, test.body))
+			require.Equal(t, test.ok, ok)
+			if !test.ok {
+				require.Nil(t, graphError)
+				return
+			}
+			require.Equal(t, test.expected, graphError.detail())
+		})
+	}
+}
+
+func TestGraphErrorIsPropertyValidationError(t *testing.T) {
+	tests := []struct {
+		name     string
+		body     string
+		expected bool
+	}{
+		{
+			name:     "when a detail has a target then it is a property validation error",
+			body:     `{"error":{"code":"BadRequest","message":"Invalid request.","details":[{"code":"InvalidValue","message":"Invalid mail.","target":"mail"}]}}`,
+			expected: true,
+		},
+		{
+			name:     "when a bad request mentions a property then it is a property validation error",
+			body:     `{"error":{"code":"Request_BadRequest","message":"Invalid value specified for property 'mailNickname'."}}`,
+			expected: true,
+		},
+		{
+			name:     "when a bad request does not mention a property then it is not a property validation error",
+			body:     `{"error":{"code":"Request_BadRequest","message":"Unsupported query."}}`,
+			expected: false,
+		},
+		{
+			name:     "when the error is not a bad request then it is not a property validation error",
+			body:     `{"error":{"code":"Authorization_RequestDenied","message":"Insufficient privileges to complete the operation on property 'mail'."}}`,
+			expected: false,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			graphError, ok := parseGraphError(testResponse(http.StatusBadRequest, test.body))
+			require.True(t, ok)
+			require.Equal(t, test.expected, graphError.isPropertyValidationError())
+		})
+	}
+}
@@ -19,10 +19,21 @@ const (
 	apiVersionPath = "{api_version}/"
 )
 
+type collectionPage struct {
+	Value     []json.RawMessage `json:"value"`
+	NextLink  string            `json:"@odata.nextLink"`
+	DeltaLink string            `json:"@odata.deltaLink"`
+}
+
 func get(http *resty.Request, url string) (*resty.Response, error) {
 	return http.Get(apiVersionPath + url)
 }
 
+func getLink(http *resty.Request, link string) (*resty.Response, error) {
+	http.QueryParam = url.Values{}
+	return http.Get(link)
+}
+
 func post(http *resty.Request, url string) (*resty.Response, error) {
 	return http.Post(apiVersionPath + url)
 }
@@ -41,13 +52,21 @@ func ensureHttpResponseSucceeded(response *resty.Response, err error) diag.Diagn
 	}
 
 	if response.IsError() {
-		return errorDiagnostics(fmt.Sprintf("Request failed with %d for: %s %q", response.StatusCode(), response.Request.Method, response.Request.URL), string(response.Body()))
+		summary := fmt.Sprintf("Request failed with %d for: %s %q", response.StatusCode(), response.Request.Method, response.Request.URL)
+		if graphError, ok := parseGraphError(response); ok {
+			return errorDiagnostics(summary, graphError.detail())
+		}
+		return errorDiagnostics(summary, string(response.Body()))
 	}
 
 	return noErrors()
 }
 
 func ensureResponseAsDynamic(response *resty.Response) (types.Dynamic, diag.Diagnostics) {
+	if len(response.Body()) == 0 {
+		return types.DynamicNull(), noErrors()
+	}
+
 	content, err := dynamic.FromJSONImplied(response.Body())
 	if err != nil {
 		return types.DynamicNull(), errorDiagnostics(fmt.Sprintf("Parse request body failed for: %s %q", response.Request.Method, response.Request.URL), string(response.Body()))
@@ -70,16 +89,31 @@ func ensureIsValidPathString(value types.String) (string, diag.Diagnostics) {
 
 func ensureRequestSetBodyFromDynamic(request *resty.Request, value types.Dynamic) diag.Diagnostics {
 	body, err := dynamic.ToJSON(value)
+	if err != nil {
+		return dynamicErrorDiagnostics("Failed to marshal request body to JSON.", err)
+	}
+
+	setRequestJSONBody(request, body)
+
+	return noErrors()
+}
+
+func ensureRequestSetBodyFromMap(request *resty.Request, value map[string]interface{}) diag.Diagnostics {
+	body, err := json.Marshal(value)
 	if err != nil {
 		return errorDiagnostics("Failed to marshal request body to JSON.", err.Error())
 	}
 
+	setRequestJSONBody(request, body)
+
+	return noErrors()
+}
+
+func setRequestJSONBody(request *resty.Request, body []byte) {
 	request.
 		SetHeader("Accept", mimeTypeApplicationJson).
 		SetHeader("Content-Type", mimeTypeApplicationJson).
 		SetBody(body)
-
-	return noErrors()
 }
 
 func ensureResponseHasObjectID(response *resty.Response) (string, diag.Diagnostics) {
@@ -105,3 +139,132 @@ func ensureGetObjectAsDynamic(http *resty.Request, url string) (types.Dynamic, d
 
 	return ensureResponseAsDynamic(response)
 }
+
+// ensureFindObjectAsDynamic is ensureGetObjectAsDynamic that reports whether
+// the object exists instead of failing when it does not.
+func ensureFindObjectAsDynamic(http *resty.Request, url string) (types.Dynamic, bool, diag.Diagnostics) {
+	response, err := get(http, url)
+	if isNotFound(response, err) {
+		return types.DynamicNull(), false, noErrors()
+	}
+
+	diags := ensureHttpResponseSucceeded(response, err)
+	if diags.HasError() {
+		return types.DynamicNull(), false, diags
+	}
+
+	content, diags := ensureResponseAsDynamic(response)
+	return content, true, diags
+}
+
+func ensureGetPage(http *resty.Request, url string, isLink bool) (*collectionPage, diag.Diagnostics) {
+	var response *resty.Response
+	var err error
+	if isLink {
+		response, err = getLink(http, url)
+	} else {
+		response, err = get(http, url)
+	}
+	diags := ensureHttpResponseSucceeded(response, err)
+	if diags.HasError() {
+		return nil, diags
+	}
+
+	var page collectionPage
+	if err := json.Unmarshal(response.Body(), &page); err != nil {
+		return nil, errorDiagnostics(fmt.Sprintf("Failed to parse response body for: %s %q", response.Request.Method, response.Request.URL), string(response.Body()))
+	}
+
+	return &page, noErrors()
+}
+
+// ensureListPages follows @odata.nextLink until every item of the collection
+// has been retrieved, or until `maxItems` items were retrieved when it is
+// greater than zero. The last page retrieved is returned with the items.
+func ensureListPages(http *resty.Request, url string, isLink bool, maxItems int64) ([]json.RawMessage, *collectionPage, diag.Diagnostics) {
+	var items []json.RawMessage
+
+	page, diags := ensureGetPage(http, url, isLink)
+	for !diags.HasError() {
+		items = append(items, page.Value...)
+		if maxItems > 0 && int64(len(items)) >= maxItems {
+			return items[:maxItems], page, diags
+		}
+		if page.NextLink == "" {
+			return items, page, diags
+		}
+		page, diags = ensureGetPage(http, page.NextLink, true)
+	}
+
+	return nil, nil, diags
+}
+
+func ensureListAsJSON(http *resty.Request, url string, maxItems int64) ([]json.RawMessage, diag.Diagnostics) {
+	items, _, diags := ensureListPages(http, url, false, maxItems)
+	return items, diags
+}
+
+func ensureListAsDynamic(http *resty.Request, url string, maxItems int64) (types.Dynamic, diag.Diagnostics) {
+	items, diags := ensureListAsJSON(http, url, maxItems)
+	if diags.HasError() {
+		return types.DynamicNull(), diags
+	}
+
+	return ensureItemsAsDynamic(items, url)
+}
+
+// ensureListDeltaAsDynamic follows @odata.nextLink until the final page of a
+// delta query and returns the changed items with its @odata.deltaLink.
+func ensureListDeltaAsDynamic(http *resty.Request, url string, isLink bool) (types.Dynamic, string, diag.Diagnostics) {
+	items, page, diags := ensureListPages(http, url, isLink, 0)
+	if diags.HasError() {
+		return types.DynamicNull(), "", diags
+	}
+
+	if page.DeltaLink == "" {
+		return types.DynamicNull(), "", errorDiagnostics(fmt.Sprintf("Delta query did not return a delta link for: %q", url), "The final page of the delta query has no @odata.deltaLink.")
+	}
+
+	content, diags := ensureItemsAsDynamic(items, url)
+	return content, page.DeltaLink, diags
+}
+
+func ensureItemsAsDynamic(items []json.RawMessage, url string) (types.Dynamic, diag.Diagnostics) {
+	if items == nil {
+		items = []json.RawMessage{}
+	}
+
+	body, err := json.Marshal(items)
+	if err != nil {
+		return types.DynamicNull(), errorDiagnostics(fmt.Sprintf("Failed to marshal items of: %q", url), err.Error())
+	}
+
+	content, err := dynamic.FromJSONImplied(body)
+	if err != nil {
+		return types.DynamicNull(), errorDiagnostics(fmt.Sprintf("Failed to parse items of: %q", url), err.Error())
+	}
+
+	return content, noErrors()
+}
+
+func ensureListObjectIDs(http *resty.Request, url string, maxItems int64) ([]string, diag.Diagnostics) {
+	http.SetQueryParam("$select", "id")
+
+	items, diags := ensureListAsJSON(http, url, maxItems)
+	if diags.HasError() {
+		return nil, diags
+	}
+
+	objectIDs := make([]string, 0, len(items))
+	for _, item := range items {
+		var content struct {
+			ID string `json:"id"`
+		}
+		if err := json.Unmarshal(item, &content); err != nil {
+			return nil, errorDiagnostics(fmt.Sprintf("Failed to parse collection item for: %q", url), string(item))
+		}
+		objectIDs = append(objectIDs, content.ID)
+	}
+
+	return objectIDs, noErrors()
+}
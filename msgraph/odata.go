@@ -0,0 +1,73 @@
+package msgraph
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/go-resty/resty/v2"
+)
+
+type odataQuery struct {
+	Filter  string
+	Select  []string
+	Expand  string
+	OrderBy []string
+	Search  string
+	Top     int64
+}
+
+// advancedQueryFilterTokens are the filter constructs that Microsoft Graph only
+// supports on directory objects as advanced queries.
+var advancedQueryFilterTokens = []string{
+	"endswith(",
+	" ne ",
+	"not(",
+	"/$count",
+}
+
+func (q odataQuery) apply(http *resty.Request) {
+	if q.Filter != "" {
+		http.SetQueryParam("$filter", q.Filter)
+	}
+	if len(q.Select) > 0 {
+		http.SetQueryParam("$select", strings.Join(q.Select, ","))
+	}
+	if q.Expand != "" {
+		http.SetQueryParam("$expand", q.Expand)
+	}
+	if len(q.OrderBy) > 0 {
+		http.SetQueryParam("$orderby", strings.Join(q.OrderBy, ","))
+	}
+	if q.Search != "" {
+		http.SetQueryParam("$search", q.Search)
+	}
+	if q.Top > 0 {
+		http.SetQueryParam("$top", strconv.FormatInt(q.Top, 10))
+	}
+
+	if q.isAdvanced() {
+		http.SetHeader("ConsistencyLevel", "eventual")
+		http.SetQueryParam("$count", "true")
+	}
+}
+
+// isAdvanced reports whether the query uses advanced query capabilities, which
+// require the `ConsistencyLevel: eventual` header and `$count=true`.
+func (q odataQuery) isAdvanced() bool {
+	if q.Search != "" {
+		return true
+	}
+
+	if q.Filter != "" && len(q.OrderBy) > 0 {
+		return true
+	}
+
+	filter := strings.ToLower(q.Filter)
+	for _, token := range advancedQueryFilterTokens {
+		if strings.Contains(filter, token) {
+			return true
+		}
+	}
+
+	return false
+}
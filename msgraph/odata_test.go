@@ -0,0 +1,105 @@
+package msgraph
+
+import (
+	"testing"
+
+	"github.com/go-resty/resty/v2"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOdataQueryApply(t *testing.T) {
+	tests := []struct {
+		name     string
+		query    odataQuery
+		advanced bool
+		params   map[string]string
+	}{
+		{
+			name:     "when there is no query then no parameters are set",
+			query:    odataQuery{},
+			advanced: false,
+			params:   map[string]string{},
+		},
+		{
+			name:     "when the filter is plain then it is not an advanced query",
+			query:    odataQuery{Filter: "displayName eq 'a'"},
+			advanced: false,
+			params:   map[string]string{"$filter": "displayName eq 'a'"},
+		},
+		{
+			name:     "when the filter uses endswith then it is an advanced query",
+			query:    odataQuery{Filter: "endswith(mail,'@contoso.com')"},
+			advanced: true,
+			params:   map[string]string{"$filter": "endswith(mail,'@contoso.com')", "$count": "true"},
+		},
+		{
+			name:     "when the filter uses ne then it is an advanced query",
+			query:    odataQuery{Filter: "displayName ne null"},
+			advanced: true,
+			params:   map[string]string{"$filter": "displayName ne null", "$count": "true"},
+		},
+		{
+			name:     "when the filter uses not then it is an advanced query",
+			query:    odataQuery{Filter: "NOT(displayName eq 'a')"},
+			advanced: true,
+			params:   map[string]string{"$filter": "NOT(displayName eq 'a')", "$count": "true"},
+		},
+		{
+			name:     "when the filter uses a count then it is an advanced query",
+			query:    odataQuery{Filter: "owners/$count eq 0"},
+			advanced: true,
+			params:   map[string]string{"$filter": "owners/$count eq 0", "$count": "true"},
+		},
+		{
+			name:     "when the filter only contains ne inside a word then it is not an advanced query",
+			query:    odataQuery{Filter: "displayName eq 'one'"},
+			advanced: false,
+			params:   map[string]string{"$filter": "displayName eq 'one'"},
+		},
+		{
+			name:     "when there is a search then it is an advanced query",
+			query:    odataQuery{Search: `"displayName:a"`},
+			advanced: true,
+			params:   map[string]string{"$search": `"displayName:a"`, "$count": "true"},
+		},
+		{
+			name:     "when a filter is combined with orderby then it is an advanced query",
+			query:    odataQuery{Filter: "displayName eq 'a'", OrderBy: []string{"displayName", "id desc"}},
+			advanced: true,
+			params:   map[string]string{"$filter": "displayName eq 'a'", "$orderby": "displayName,id desc", "$count": "true"},
+		},
+		{
+			name:     "when there is only an orderby then it is not an advanced query",
+			query:    odataQuery{OrderBy: []string{"displayName"}},
+			advanced: false,
+			params:   map[string]string{"$orderby": "displayName"},
+		},
+		{
+			name:     "when select, expand and top are set then they are passed through",
+			query:    odataQuery{Select: []string{"id", "displayName"}, Expand: "owners", Top: 2},
+			advanced: false,
+			params:   map[string]string{"$select": "id,displayName", "$expand": "owners", "$top": "2"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			require.Equal(t, test.advanced, test.query.isAdvanced())
+
+			http := resty.New().R()
+			test.query.apply(http)
+
+			params := map[string]string{}
+			for key := range http.QueryParam {
+				params[key] = http.QueryParam.Get(key)
+			}
+			require.Equal(t, test.params, params)
+
+			if test.advanced {
+				require.Equal(t, "eventual", http.Header.Get("ConsistencyLevel"))
+			} else {
+				require.Empty(t, http.Header.Get("ConsistencyLevel"))
+			}
+		})
+	}
+}
@@ -0,0 +1,155 @@
+package msgraph
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/GoodCloudWorks/terraform-provider-msgraph/msgraph/dynamic"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// ensureDecodeProperties returns the JSON object of properties given as a JSON
+// string, e.g. `jsonencode(...)`. Objects are returned as is.
+func ensureDecodeProperties(value types.Dynamic) (types.Dynamic, diag.Diagnostics) {
+	decoded, err := dynamic.Decode(value)
+	if err != nil {
+		return types.DynamicNull(), errorDiagnostics("Failed to parse properties as a JSON object.", err.Error())
+	}
+	return decoded, noErrors()
+}
+
+func ensureDynamicAsMap(value types.Dynamic) (map[string]json.RawMessage, diag.Diagnostics) {
+	if value.IsNull() {
+		return map[string]json.RawMessage{}, noErrors()
+	}
+
+	value, diags := ensureDecodeProperties(value)
+	if diags.HasError() {
+		return nil, diags
+	}
+
+	body, err := dynamic.ToJSON(value)
+	if err != nil {
+		return nil, dynamicErrorDiagnostics("Failed to marshal properties to JSON.", err)
+	}
+
+	return ensureJSONAsMap(body)
+}
+
+func ensureJSONAsMap(body []byte) (map[string]json.RawMessage, diag.Diagnostics) {
+	content := map[string]json.RawMessage{}
+	if len(body) == 0 || string(body) == "null" {
+		return content, noErrors()
+	}
+
+	if err := json.Unmarshal(body, &content); err != nil {
+		return nil, errorDiagnostics("Failed to parse properties as a JSON object.", string(body))
+	}
+
+	return content, noErrors()
+}
+
+// ensurePickProperties returns the top-level values of `content` for every key
+// present in `properties`, and the keys missing from `content`.
+func ensurePickProperties(content types.Dynamic, properties types.Dynamic) (map[string]json.RawMessage, []string, diag.Diagnostics) {
+	contentMap, diags := ensureDynamicAsMap(content)
+	if diags.HasError() {
+		return nil, nil, diags
+	}
+
+	propertiesMap, diags := ensureDynamicAsMap(properties)
+	if diags.HasError() {
+		return nil, nil, diags
+	}
+
+	picked := make(map[string]json.RawMessage, len(propertiesMap))
+	var missing []string
+	for key := range propertiesMap {
+		if value, ok := contentMap[key]; ok {
+			picked[key] = value
+		} else {
+			missing = append(missing, key)
+		}
+	}
+
+	return picked, missing, noErrors()
+}
+
+// ensureLookupJSONPath returns the value found at the dot separated `path`
+// within `content`, e.g. `passwordCredential.keyId`.
+func ensureLookupJSONPath(content []byte, path string) (json.RawMessage, diag.Diagnostics) {
+	value := json.RawMessage(content)
+	for _, key := range strings.Split(path, ".") {
+		object, diags := ensureJSONAsMap(value)
+		if diags.HasError() {
+			return nil, diags
+		}
+
+		var ok bool
+		if value, ok = object[key]; !ok {
+			return nil, errorDiagnostics(fmt.Sprintf("Failed to find %q in the response.", path), string(content))
+		}
+	}
+
+	return value, noErrors()
+}
+
+// ensureMergeProperties returns `properties` deep merged with `overrides` as a
+// JSON object. Values of `overrides` take precedence, except for nested objects
+// which are merged.
+func ensureMergeProperties(properties types.Dynamic, overrides types.Dynamic) ([]byte, diag.Diagnostics) {
+	var merged map[string]interface{}
+	for _, value := range []types.Dynamic{properties, overrides} {
+		if value.IsNull() {
+			continue
+		}
+
+		value, diags := ensureDecodeProperties(value)
+		if diags.HasError() {
+			return nil, diags
+		}
+
+		body, err := dynamic.ToJSON(value)
+		if err != nil {
+			return nil, dynamicErrorDiagnostics("Failed to marshal properties to JSON.", err)
+		}
+
+		var object map[string]interface{}
+		if err := dynamic.UnmarshalJSON(body, &object); err != nil {
+			return nil, errorDiagnostics("Failed to parse properties as a JSON object.", string(body))
+		}
+
+		merged = mergeObjects(merged, object)
+	}
+
+	if merged == nil {
+		merged = map[string]interface{}{}
+	}
+
+	body, err := json.Marshal(merged)
+	if err != nil {
+		return nil, dynamicErrorDiagnostics("Failed to marshal properties to JSON.", err)
+	}
+
+	return body, noErrors()
+}
+
+func mergeObjects(target, source map[string]interface{}) map[string]interface{} {
+	if target == nil {
+		return source
+	}
+
+	for key, sourceValue := range source {
+		targetObject, targetIsObject := target[key].(map[string]interface{})
+		sourceObject, sourceIsObject := sourceValue.(map[string]interface{})
+		if targetIsObject && sourceIsObject {
+			target[key] = mergeObjects(targetObject, sourceObject)
+		} else {
+			target[key] = sourceValue
+		}
+	}
+
+	return target
+}
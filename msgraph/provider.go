@@ -9,11 +9,17 @@ import (
 
 var dataSources = []func() datasource.DataSource{
 	NewMsGraphProviderConfigDataSource,
+	NewMsGraphDeltaDataSource,
 	NewMsGraphObjectDataSource,
+	NewMsGraphObjectsDataSource,
 }
 
 var resources = []func() resource.Resource{
+	NewMsGraphActionResource,
 	NewMsGraphObjectResource,
+	NewMsGraphReferenceResource,
+	NewMsGraphReferencesResource,
+	NewMsGraphUpdateResource,
 }
 
 func NewProvider() provider.Provider {
@@ -0,0 +1,373 @@
+package provider
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	// Microsoft Graph accepts at most 20 requests in a single $batch request.
+	maxBatchSize = 20
+
+	batchWindow = 10 * time.Millisecond
+)
+
+// batchTransport coalesces concurrent GET requests to Microsoft Graph into
+// $batch requests and fans the individual responses back out to the callers.
+// When the $batch request itself is throttled, every caller receives the
+// throttling response so that the retry policy backs off; on other failures the
+// requests are sent individually.
+type batchTransport struct {
+	next    http.RoundTripper
+	baseURL string
+	window  time.Duration
+
+	mutex   sync.Mutex
+	pending map[batchKey][]*batchItem
+}
+
+type batchKey struct {
+	apiVersion    string
+	authorization string
+}
+
+type batchItem struct {
+	request *http.Request
+	done    chan batchResult
+}
+
+type batchResult struct {
+	response *http.Response
+	err      error
+}
+
+type batchRequest struct {
+	ID      string            `json:"id"`
+	Method  string            `json:"method"`
+	URL     string            `json:"url"`
+	Headers map[string]string `json:"headers,omitempty"`
+}
+
+// batchThrottledError is returned by sendBatch when Microsoft Graph throttles
+// the $batch request itself.
+type batchThrottledError struct {
+	statusCode int
+	header     http.Header
+	body       []byte
+}
+
+func (e *batchThrottledError) Error() string {
+	return fmt.Sprintf("batch request throttled with %d", e.statusCode)
+}
+
+type batchResponse struct {
+	ID      string            `json:"id"`
+	Status  int               `json:"status"`
+	Headers map[string]string `json:"headers"`
+	Body    json.RawMessage   `json:"body"`
+}
+
+var _ http.RoundTripper = &batchTransport{}
+
+func newBatchTransport(next http.RoundTripper, baseURL string) *batchTransport {
+	return &batchTransport{
+		next:    next,
+		baseURL: baseURL,
+		window:  batchWindow,
+		pending: map[batchKey][]*batchItem{},
+	}
+}
+
+func (t *batchTransport) RoundTrip(request *http.Request) (*http.Response, error) {
+	key, ok := t.batchKeyOf(request)
+	if !ok {
+		return t.next.RoundTrip(request)
+	}
+
+	item := &batchItem{
+		request: request,
+		done:    make(chan batchResult, 1),
+	}
+	t.enqueue(key, item)
+
+	select {
+	case result := <-item.done:
+		return result.response, result.err
+	case <-request.Context().Done():
+		t.dequeue(key, item)
+		return nil, request.Context().Err()
+	}
+}
+
+func (t *batchTransport) batchKeyOf(request *http.Request) (batchKey, bool) {
+	if request.Method != http.MethodGet || request.URL.Scheme+"://"+request.URL.Host+"/" != t.baseURL {
+		return batchKey{}, false
+	}
+
+	segments := strings.SplitN(strings.TrimPrefix(request.URL.Path, "/"), "/", 2)
+	if len(segments) != 2 || segments[1] == "" || strings.HasPrefix(segments[1], "$batch") {
+		return batchKey{}, false
+	}
+
+	return batchKey{
+		apiVersion:    segments[0],
+		authorization: request.Header.Get("Authorization"),
+	}, true
+}
+
+func (t *batchTransport) enqueue(key batchKey, item *batchItem) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	t.pending[key] = append(t.pending[key], item)
+
+	switch len(t.pending[key]) {
+	case 1:
+		time.AfterFunc(t.window, func() { t.flush(key) })
+	case maxBatchSize:
+		go t.flush(key)
+	}
+}
+
+// dequeue removes an item whose caller gave up before its batch was flushed.
+func (t *batchTransport) dequeue(key batchKey, item *batchItem) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	items := t.pending[key]
+	for i, pending := range items {
+		if pending == item {
+			items = append(items[:i:i], items[i+1:]...)
+			break
+		}
+	}
+
+	if len(items) == 0 {
+		delete(t.pending, key)
+	} else {
+		t.pending[key] = items
+	}
+}
+
+func (t *batchTransport) flush(key batchKey) {
+	t.mutex.Lock()
+	items := t.pending[key]
+	// Callers that were cancelled may not have dequeued their item yet.
+	active := items[:0:0]
+	for _, item := range items {
+		if item.request.Context().Err() == nil {
+			active = append(active, item)
+		}
+	}
+	items = active
+	if len(items) > maxBatchSize {
+		t.pending[key] = items[maxBatchSize:]
+		items = items[:maxBatchSize]
+		time.AfterFunc(t.window, func() { t.flush(key) })
+	} else {
+		delete(t.pending, key)
+	}
+	t.mutex.Unlock()
+
+	switch len(items) {
+	case 0:
+		return
+	case 1:
+		t.send(items[0])
+		return
+	}
+
+	responses, err := t.sendBatch(key, items)
+
+	var throttled *batchThrottledError
+	if errors.As(err, &throttled) {
+		// Sending the requests individually would only add to the load of a
+		// throttled tenant, so each caller retries after Retry-After instead.
+		for _, item := range items {
+			item.done <- batchResult{response: throttled.asHttpResponse(item.request)}
+		}
+		return
+	}
+
+	if err != nil {
+		for _, item := range items {
+			go t.send(item)
+		}
+		return
+	}
+
+	for i, item := range items {
+		response, ok := responses[strconv.Itoa(i)]
+		if !ok {
+			go t.send(item)
+			continue
+		}
+		item.done <- batchResult{response: response.asHttpResponse(item.request)}
+	}
+}
+
+func (t *batchTransport) send(item *batchItem) {
+	response, err := t.next.RoundTrip(item.request)
+	item.done <- batchResult{response: response, err: err}
+}
+
+func (t *batchTransport) sendBatch(key batchKey, items []*batchItem) (map[string]batchResponse, error) {
+	requests := make([]batchRequest, 0, len(items))
+	for i, item := range items {
+		requests = append(requests, batchRequest{
+			ID:      strconv.Itoa(i),
+			Method:  item.request.Method,
+			URL:     batchRequestURL(item.request, key),
+			Headers: batchRequestHeaders(item.request),
+		})
+	}
+
+	body, err := json.Marshal(map[string]interface{}{"requests": requests})
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := batchContext(items)
+	defer cancel()
+
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, t.baseURL+key.apiVersion+"/$batch", bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	request.Header.Set("Authorization", key.authorization)
+	request.Header.Set("Content-Type", "application/json")
+	request.Header.Set("Accept", "application/json")
+
+	response, err := t.next.RoundTrip(request)
+	if err != nil {
+		return nil, err
+	}
+	defer response.Body.Close()
+
+	if isThrottled(response.StatusCode) {
+		body, _ := io.ReadAll(response.Body)
+		return nil, &batchThrottledError{statusCode: response.StatusCode, header: response.Header.Clone(), body: body}
+	}
+
+	if response.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("batch request failed with %d", response.StatusCode)
+	}
+
+	var content struct {
+		Responses []batchResponse `json:"responses"`
+	}
+	if err := json.NewDecoder(response.Body).Decode(&content); err != nil {
+		return nil, err
+	}
+
+	responses := make(map[string]batchResponse, len(content.Responses))
+	for _, response := range content.Responses {
+		responses[response.ID] = response
+	}
+
+	return responses, nil
+}
+
+// batchContext returns a context for the $batch request that is only cancelled
+// once every caller of the batch has been cancelled.
+func batchContext(items []*batchItem) (context.Context, context.CancelFunc) {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	remaining := atomic.Int64{}
+	remaining.Store(int64(len(items)))
+
+	stops := make([]func() bool, 0, len(items))
+	for _, item := range items {
+		stops = append(stops, context.AfterFunc(item.request.Context(), func() {
+			if remaining.Add(-1) == 0 {
+				cancel()
+			}
+		}))
+	}
+
+	return ctx, func() {
+		for _, stop := range stops {
+			stop()
+		}
+		cancel()
+	}
+}
+
+func isThrottled(statusCode int) bool {
+	return statusCode == http.StatusTooManyRequests || statusCode == http.StatusServiceUnavailable
+}
+
+func batchRequestURL(request *http.Request, key batchKey) string {
+	url := strings.TrimPrefix(request.URL.EscapedPath(), "/"+key.apiVersion)
+	if request.URL.RawQuery != "" {
+		url += "?" + request.URL.RawQuery
+	}
+	return url
+}
+
+func batchRequestHeaders(request *http.Request) map[string]string {
+	headers := map[string]string{}
+	for name := range request.Header {
+		switch http.CanonicalHeaderKey(name) {
+		case "Authorization", "Accept-Encoding", "User-Agent", "Content-Length":
+			continue
+		}
+		headers[name] = request.Header.Get(name)
+	}
+	return headers
+}
+
+func (e *batchThrottledError) asHttpResponse(request *http.Request) *http.Response {
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", e.statusCode, http.StatusText(e.statusCode)),
+		StatusCode:    e.statusCode,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        e.header.Clone(),
+		Body:          io.NopCloser(bytes.NewReader(e.body)),
+		ContentLength: int64(len(e.body)),
+		Request:       request,
+	}
+}
+
+func (r batchResponse) asHttpResponse(request *http.Request) *http.Response {
+	header := http.Header{}
+	for name, value := range r.Headers {
+		header.Set(name, value)
+	}
+
+	body := []byte(r.Body)
+	if !strings.Contains(header.Get("Content-Type"), "json") {
+		// Non-JSON bodies are returned as base64 encoded strings.
+		var encoded string
+		if err := json.Unmarshal(body, &encoded); err == nil {
+			if decoded, err := base64.StdEncoding.DecodeString(encoded); err == nil {
+				body = decoded
+			}
+		}
+	}
+
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", r.Status, http.StatusText(r.Status)),
+		StatusCode:    r.Status,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          io.NopCloser(bytes.NewReader(body)),
+		ContentLength: int64(len(body)),
+		Request:       request,
+	}
+}
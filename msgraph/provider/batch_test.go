@@ -0,0 +1,218 @@
+package provider
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+type fakeTransport struct {
+	mutex       sync.Mutex
+	batchCalls  int
+	batched     []string
+	calls       []string
+	batchCode   int
+	batchHeader http.Header
+	innerCode   int
+}
+
+func (f *fakeTransport) RoundTrip(request *http.Request) (*http.Response, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if strings.HasSuffix(request.URL.Path, "/$batch") {
+		f.batchCalls++
+		if f.batchCode != 0 {
+			response := fakeResponse(request, f.batchCode, `{}`)
+			for name, values := range f.batchHeader {
+				response.Header[name] = values
+			}
+			return response, nil
+		}
+
+		var content struct {
+			Requests []batchRequest `json:"requests"`
+		}
+		if err := json.NewDecoder(request.Body).Decode(&content); err != nil {
+			return nil, err
+		}
+
+		innerCode := f.innerCode
+		if innerCode == 0 {
+			innerCode = http.StatusOK
+		}
+
+		var responses []map[string]interface{}
+		for _, r := range content.Requests {
+			f.batched = append(f.batched, r.URL)
+			responses = append(responses, map[string]interface{}{
+				"id":      r.ID,
+				"status":  innerCode,
+				"headers": map[string]string{"Content-Type": "application/json"},
+				"body":    map[string]string{"url": r.URL},
+			})
+		}
+		body, _ := json.Marshal(map[string]interface{}{"responses": responses})
+		return fakeResponse(request, http.StatusOK, string(body)), nil
+	}
+
+	f.calls = append(f.calls, request.Method+" "+request.URL.Path)
+	return fakeResponse(request, http.StatusOK, fmt.Sprintf(`{"url":%q}`, strings.TrimPrefix(request.URL.Path, "/v1.0"))), nil
+}
+
+func fakeResponse(request *http.Request, code int, body string) *http.Response {
+	return &http.Response{
+		StatusCode: code,
+		Header:     http.Header{"Content-Type": []string{"application/json"}},
+		Body:       io.NopCloser(bytes.NewBufferString(body)),
+		Request:    request,
+	}
+}
+
+func roundTripConcurrently(t *testing.T, transport http.RoundTripper, count int) []string {
+	results := make([]string, count)
+
+	var wg sync.WaitGroup
+	for i := 0; i < count; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+
+			request, err := http.NewRequest(http.MethodGet, fmt.Sprintf("%sv1.0/groups/%d", graphBaseURL, i), nil)
+			require.NoError(t, err)
+
+			response, err := transport.RoundTrip(request)
+			require.NoError(t, err)
+			require.Equal(t, http.StatusOK, response.StatusCode)
+
+			var content struct {
+				URL string `json:"url"`
+			}
+			require.NoError(t, json.NewDecoder(response.Body).Decode(&content))
+			results[i] = content.URL
+		}(i)
+	}
+	wg.Wait()
+
+	return results
+}
+
+func TestBatchTransport(t *testing.T) {
+	t.Run("when concurrent GET requests then they are sent as a batch", func(t *testing.T) {
+		next := &fakeTransport{}
+		transport := newBatchTransport(next, graphBaseURL)
+		transport.window = 200 * time.Millisecond
+
+		results := roundTripConcurrently(t, transport, 5)
+
+		for i, result := range results {
+			require.Equal(t, fmt.Sprintf("/groups/%d", i), result)
+		}
+		require.Equal(t, 1, next.batchCalls)
+		require.Empty(t, next.calls)
+	})
+
+	t.Run("when more GET requests than the batch size then multiple batches are sent", func(t *testing.T) {
+		next := &fakeTransport{}
+		transport := newBatchTransport(next, graphBaseURL)
+		transport.window = 200 * time.Millisecond
+
+		results := roundTripConcurrently(t, transport, maxBatchSize+5)
+
+		for i, result := range results {
+			require.Equal(t, fmt.Sprintf("/groups/%d", i), result)
+		}
+		require.GreaterOrEqual(t, next.batchCalls, 2)
+	})
+
+	t.Run("when batch request fails then requests are sent individually", func(t *testing.T) {
+		next := &fakeTransport{batchCode: http.StatusBadGateway}
+		transport := newBatchTransport(next, graphBaseURL)
+		transport.window = 200 * time.Millisecond
+
+		results := roundTripConcurrently(t, transport, 3)
+
+		for i, result := range results {
+			require.Equal(t, fmt.Sprintf("/groups/%d", i), result)
+		}
+		require.Equal(t, 1, next.batchCalls)
+		require.Len(t, next.calls, 3)
+	})
+
+	t.Run("when batch request is throttled then every request receives the throttling response", func(t *testing.T) {
+		next := &fakeTransport{batchCode: http.StatusTooManyRequests, batchHeader: http.Header{"Retry-After": []string{"7"}}}
+		transport := newBatchTransport(next, graphBaseURL)
+		transport.window = 200 * time.Millisecond
+
+		var wg sync.WaitGroup
+		for i := 0; i < 3; i++ {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+
+				request, err := http.NewRequest(http.MethodGet, fmt.Sprintf("%sv1.0/groups/%d", graphBaseURL, i), nil)
+				require.NoError(t, err)
+
+				response, err := transport.RoundTrip(request)
+				require.NoError(t, err)
+				require.Equal(t, http.StatusTooManyRequests, response.StatusCode)
+				require.Equal(t, "7", response.Header.Get("Retry-After"))
+				require.Same(t, request, response.Request)
+			}(i)
+		}
+		wg.Wait()
+
+		require.Equal(t, 1, next.batchCalls)
+		require.Empty(t, next.calls)
+	})
+
+	t.Run("when a caller cancels during the batch window then its request is not sent", func(t *testing.T) {
+		next := &fakeTransport{}
+		transport := newBatchTransport(next, graphBaseURL)
+		transport.window = 200 * time.Millisecond
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancelled := make(chan error, 1)
+		go func() {
+			request, err := http.NewRequestWithContext(ctx, http.MethodGet, graphBaseURL+"v1.0/groups/cancelled", nil)
+			require.NoError(t, err)
+
+			_, err = transport.RoundTrip(request)
+			cancelled <- err
+		}()
+
+		time.AfterFunc(50*time.Millisecond, cancel)
+		results := roundTripConcurrently(t, transport, 2)
+
+		require.ErrorIs(t, <-cancelled, context.Canceled)
+		for i, result := range results {
+			require.Equal(t, fmt.Sprintf("/groups/%d", i), result)
+		}
+		require.Equal(t, 1, next.batchCalls)
+		require.ElementsMatch(t, []string{"/groups/0", "/groups/1"}, next.batched)
+		require.Empty(t, next.calls)
+	})
+
+	t.Run("when request is not a GET then it is sent individually", func(t *testing.T) {
+		next := &fakeTransport{}
+		transport := newBatchTransport(next, graphBaseURL)
+		transport.window = 200 * time.Millisecond
+
+		request, err := http.NewRequest(http.MethodPatch, graphBaseURL+"v1.0/groups/1", nil)
+		require.NoError(t, err)
+
+		_, err = transport.RoundTrip(request)
+		require.NoError(t, err)
+		require.Equal(t, 0, next.batchCalls)
+		require.Equal(t, []string{"PATCH /v1.0/groups/1"}, next.calls)
+	})
+}
@@ -2,17 +2,19 @@ package provider
 
 import (
 	"context"
-	"net/http"
-	"time"
+	"strings"
 
 	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
 	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
 	"github.com/GoodCloudWorks/terraform-provider-msgraph/msgraph/client"
 	"github.com/GoodCloudWorks/terraform-provider-msgraph/msgraph/credentials"
 	"github.com/go-resty/resty/v2"
+	"github.com/google/uuid"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 )
 
+const graphBaseURL = client.GraphBaseURL
+
 type msGraphProviderClient struct {
 	scopes     []string
 	resty      *resty.Client
@@ -39,6 +41,14 @@ func (client *msGraphProviderClient) R(context context.Context, apiVersion types
 	return request
 }
 
+func (client *msGraphProviderClient) BaseURL(apiVersion types.String) string {
+	version := client.resty.PathParams["api_version"]
+	if !apiVersion.IsNull() {
+		version = apiVersion.ValueString()
+	}
+	return strings.TrimSuffix(client.resty.BaseURL, "/") + "/" + version + "/"
+}
+
 func (data *MsGraphProviderData) NewClient() (*msGraphProviderClient, error) {
 	credentialOptions := &credentials.CredentialOptions{
 		TenantID: data.TenantID.ValueString(),
@@ -65,20 +75,25 @@ func (data *MsGraphProviderData) NewClient() (*msGraphProviderClient, error) {
 	}
 
 	client := resty.New()
-	client.BaseURL = "https://graph.microsoft.com/"
+	client.BaseURL = graphBaseURL
 	client.SetPathParam("api_version", data.ApiVersion.ValueString())
 
-	client.SetRetryCount(30)
-	client.SetRetryWaitTime(1 * time.Second)
-	client.SetRetryMaxWaitTime(30 * time.Second)
-	client.AddRetryCondition(
-		func(r *resty.Response, err error) bool {
-			return r.StatusCode() == http.StatusTooManyRequests ||
-				r.StatusCode() == http.StatusInternalServerError ||
-				r.StatusCode() == http.StatusServiceUnavailable ||
-				r.StatusCode() == http.StatusGatewayTimeout
-		},
-	)
+	transport := client.GetClient().Transport
+	if data.BatchRequests.ValueBool() {
+		transport = newBatchTransport(transport, graphBaseURL)
+	}
+	// The limiter sits above batching, so that it counts every request of a
+	// $batch and adapts to the status of each of them.
+	if maxConcurrentRequests := int(data.MaxConcurrentRequests.ValueInt64()); maxConcurrentRequests > 0 {
+		transport = newLimiterTransport(transport, maxConcurrentRequests, data.AdaptiveConcurrency.ValueBool())
+	}
+	client.SetTransport(transport)
+
+	retryPolicy, err := data.Retry.retryPolicy()
+	if err != nil {
+		return nil, err
+	}
+	retryPolicy.apply(client)
 
 	client.OnBeforeRequest(func(c *resty.Client, req *resty.Request) error {
 		token, err := credential.GetToken(req.Context(), policy.TokenRequestOptions{
@@ -88,6 +103,9 @@ func (data *MsGraphProviderData) NewClient() (*msGraphProviderClient, error) {
 			return err
 		}
 		req.SetAuthScheme("Bearer").SetAuthToken(token.Token)
+		// The client-request-id correlates each attempt with Microsoft Graph
+		// logs, so it must not be shared by retries or reused requests.
+		req.SetHeader("client-request-id", uuid.NewString())
 		return nil
 	})
 
@@ -23,6 +23,13 @@ type MsGraphProviderData struct {
 	OIDCRequestURL    types.String `tfsdk:"oidc_request_url"`
 	OIDCToken         types.String `tfsdk:"oidc_token"`
 	OIDCTokenFilePath types.String `tfsdk:"oidc_token_file_path"`
+
+	BatchRequests types.Bool `tfsdk:"batch_requests"`
+
+	MaxConcurrentRequests types.Int64 `tfsdk:"max_concurrent_requests"`
+	AdaptiveConcurrency   types.Bool  `tfsdk:"adaptive_concurrency"`
+
+	Retry *MsGraphProviderRetryData `tfsdk:"retry"`
 }
 
 func (data *MsGraphProviderData) Configure() diag.Diagnostics {
@@ -67,6 +74,15 @@ func (data *MsGraphProviderData) read() diag.Diagnostics {
 	// CLI
 	data.UseCLI = defaultIsTrue(readBoolFromEnvironment(data.UseCLI, "ARM_USE_CLI"))
 
+	// Batching
+	data.BatchRequests = defaultIsTrue(data.BatchRequests)
+
+	// Concurrency
+	if data.MaxConcurrentRequests.IsNull() {
+		data.MaxConcurrentRequests = types.Int64Value(defaultMaxConcurrentRequests)
+	}
+	data.AdaptiveConcurrency = defaultIsTrue(data.AdaptiveConcurrency)
+
 	return diag
 }
 
@@ -0,0 +1,115 @@
+package provider
+
+import (
+	"math"
+	"net/http"
+	"strconv"
+	"sync"
+)
+
+const defaultMaxConcurrentRequests = 20
+
+// limiterTransport limits the number of requests in flight to Microsoft Graph.
+// In adaptive mode the limit is shrunk multiplicatively when Microsoft Graph
+// signals throttling and grown additively back to the maximum on success, so
+// all resources and data sources of the provider back off together.
+type limiterTransport struct {
+	next     http.RoundTripper
+	max      int
+	adaptive bool
+
+	mutex    sync.Mutex
+	limit    float64
+	inFlight int
+	changed  chan struct{}
+}
+
+var _ http.RoundTripper = &limiterTransport{}
+
+func newLimiterTransport(next http.RoundTripper, max int, adaptive bool) *limiterTransport {
+	return &limiterTransport{
+		next:     next,
+		max:      max,
+		adaptive: adaptive,
+		limit:    float64(max),
+		changed:  make(chan struct{}),
+	}
+}
+
+func (t *limiterTransport) RoundTrip(request *http.Request) (*http.Response, error) {
+	if err := t.acquire(request); err != nil {
+		return nil, err
+	}
+
+	response, err := t.next.RoundTrip(request)
+	t.release(response)
+
+	return response, err
+}
+
+func (t *limiterTransport) acquire(request *http.Request) error {
+	t.mutex.Lock()
+	for t.inFlight >= t.currentLimit() {
+		changed := t.changed
+		t.mutex.Unlock()
+
+		select {
+		case <-changed:
+		case <-request.Context().Done():
+			return request.Context().Err()
+		}
+
+		t.mutex.Lock()
+	}
+	t.inFlight++
+	t.mutex.Unlock()
+
+	return nil
+}
+
+func (t *limiterTransport) release(response *http.Response) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	t.inFlight--
+	if t.adaptive && response != nil {
+		t.adapt(response)
+	}
+
+	close(t.changed)
+	t.changed = make(chan struct{})
+}
+
+func (t *limiterTransport) adapt(response *http.Response) {
+	switch {
+	case response.StatusCode == http.StatusTooManyRequests:
+		t.limit = math.Max(1, t.limit/2)
+	case isThrottlePressure(response.Header):
+		t.limit = math.Max(1, t.limit*0.75)
+	case response.StatusCode < http.StatusBadRequest:
+		// Every request grows the limit by one request per round trip, while
+		// each resource unit it costs beyond the first consumes the same share
+		// of the throttling budget, so expensive requests shrink the limit.
+		growth := (2 - resourceUnits(response.Header)) / t.limit
+		t.limit = math.Max(1, math.Min(float64(t.max), t.limit+growth))
+	}
+}
+
+func (t *limiterTransport) currentLimit() int {
+	return max(1, int(t.limit))
+}
+
+// isThrottlePressure reports whether Microsoft Graph signals that the
+// application is close to its throttling limit.
+func isThrottlePressure(header http.Header) bool {
+	value, err := strconv.ParseFloat(header.Get("x-ms-throttle-limit-percentage"), 64)
+	return err == nil && value >= 0.8
+}
+
+func resourceUnits(header http.Header) float64 {
+	value, err := strconv.ParseFloat(header.Get("x-ms-resource-unit"), 64)
+	if err != nil || value < 1 {
+		return 1
+	}
+	return value
+}
@@ -0,0 +1,139 @@
+package provider
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+type blockingTransport struct {
+	inFlight    atomic.Int32
+	maxInFlight atomic.Int32
+	statusCode  int
+	header      http.Header
+}
+
+func (b *blockingTransport) RoundTrip(request *http.Request) (*http.Response, error) {
+	inFlight := b.inFlight.Add(1)
+	defer b.inFlight.Add(-1)
+
+	for {
+		current := b.maxInFlight.Load()
+		if inFlight <= current || b.maxInFlight.CompareAndSwap(current, inFlight) {
+			break
+		}
+	}
+
+	time.Sleep(20 * time.Millisecond)
+
+	response := fakeResponse(request, b.statusCode, `{}`)
+	for name, values := range b.header {
+		response.Header[name] = values
+	}
+	return response, nil
+}
+
+func roundTripLimited(t *testing.T, transport http.RoundTripper, count int) {
+	var wg sync.WaitGroup
+	for i := 0; i < count; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			request, err := http.NewRequest(http.MethodGet, graphBaseURL+"v1.0/groups", nil)
+			require.NoError(t, err)
+
+			_, err = transport.RoundTrip(request)
+			require.NoError(t, err)
+		}()
+	}
+	wg.Wait()
+}
+
+func TestLimiterTransport(t *testing.T) {
+	t.Run("when more requests than the limit then they wait", func(t *testing.T) {
+		next := &blockingTransport{statusCode: http.StatusOK}
+		transport := newLimiterTransport(next, 3, false)
+
+		roundTripLimited(t, transport, 10)
+
+		require.LessOrEqual(t, next.maxInFlight.Load(), int32(3))
+	})
+
+	t.Run("when throttled then the limit shrinks", func(t *testing.T) {
+		next := &blockingTransport{statusCode: http.StatusTooManyRequests}
+		transport := newLimiterTransport(next, 8, true)
+
+		roundTripLimited(t, transport, 4)
+
+		require.Equal(t, 1, transport.currentLimit())
+	})
+
+	t.Run("when close to the throttling limit then the limit shrinks", func(t *testing.T) {
+		next := &blockingTransport{statusCode: http.StatusOK, header: http.Header{"X-Ms-Throttle-Limit-Percentage": []string{"0.9"}}}
+		transport := newLimiterTransport(next, 8, true)
+
+		roundTripLimited(t, transport, 1)
+
+		require.Equal(t, 6, transport.currentLimit())
+	})
+
+	t.Run("when requests cost several resource units then the limit shrinks", func(t *testing.T) {
+		next := &blockingTransport{statusCode: http.StatusOK, header: http.Header{"X-Ms-Resource-Unit": []string{"5"}}}
+		transport := newLimiterTransport(next, 8, true)
+
+		roundTripLimited(t, transport, 1)
+
+		require.Equal(t, 7, transport.currentLimit())
+	})
+
+	t.Run("when requests cost a single resource unit then the limit does not shrink", func(t *testing.T) {
+		next := &blockingTransport{statusCode: http.StatusOK, header: http.Header{"X-Ms-Resource-Unit": []string{"1"}}}
+		transport := newLimiterTransport(next, 8, true)
+
+		roundTripLimited(t, transport, 4)
+
+		require.Equal(t, 8, transport.currentLimit())
+	})
+
+	t.Run("when requests in a batch are throttled then the limit shrinks", func(t *testing.T) {
+		next := &fakeTransport{innerCode: http.StatusTooManyRequests}
+		batch := newBatchTransport(next, graphBaseURL)
+		batch.window = 200 * time.Millisecond
+		transport := newLimiterTransport(batch, 8, true)
+
+		roundTripLimited(t, transport, 4)
+
+		require.Equal(t, 1, next.batchCalls)
+		require.Equal(t, 1, transport.currentLimit())
+	})
+
+	t.Run("when requests succeed then the limit grows back", func(t *testing.T) {
+		next := &blockingTransport{statusCode: http.StatusOK}
+		transport := newLimiterTransport(next, 4, true)
+		transport.limit = 1
+
+		roundTripLimited(t, transport, 20)
+
+		require.Equal(t, 4, transport.currentLimit())
+	})
+
+	t.Run("when context is cancelled while waiting then an error is returned", func(t *testing.T) {
+		transport := newLimiterTransport(&blockingTransport{statusCode: http.StatusOK}, 1, false)
+		transport.inFlight = 1
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		request, err := http.NewRequestWithContext(ctx, http.MethodGet, graphBaseURL+"v1.0/groups", nil)
+		require.NoError(t, err)
+
+		_, err = transport.RoundTrip(request)
+		require.ErrorIs(t, err, context.Canceled)
+	})
+}
@@ -3,6 +3,7 @@ package provider
 import (
 	"context"
 
+	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
 	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
 	"github.com/hashicorp/terraform-plugin-framework/datasource"
 	"github.com/hashicorp/terraform-plugin-framework/provider"
@@ -92,6 +93,59 @@ func (*MsGraphProvider) Schema(ctx context.Context, req provider.SchemaRequest,
 				Optional:    true,
 				Description: "The path to a file containing an OIDC ID token for use when authenticating as a Service Principal using OpenID Connect.",
 			},
+
+			"batch_requests": schema.BoolAttribute{
+				Optional:    true,
+				Description: "Coalesce concurrent GET requests into Microsoft Graph `$batch` requests, default is `true`.",
+			},
+
+			"max_concurrent_requests": schema.Int64Attribute{
+				Optional:    true,
+				Description: "The maximum number of concurrent requests to Microsoft Graph, shared by all resources and data sources, default is `20`. Use `0` to disable the limit.",
+				Validators: []validator.Int64{
+					int64validator.AtLeast(0),
+				},
+			},
+
+			"adaptive_concurrency": schema.BoolAttribute{
+				Optional:    true,
+				Description: "Shrink the number of concurrent requests when Microsoft Graph throttles or requests cost several resource units, and grow it back up to `max_concurrent_requests` when requests succeed, default is `true`.",
+			},
+		},
+		Blocks: map[string]schema.Block{
+			"retry": schema.SingleNestedBlock{
+				Description: "The retry policy for failed requests. `Retry-After` and `x-ms-retry-after-ms` response headers are always honored. Non-idempotent requests, such as `POST`, are only retried on throttling or when the resource can verify that the request had no effect.",
+				Attributes: map[string]schema.Attribute{
+					"max_retries": schema.Int64Attribute{
+						Optional:    true,
+						Description: "The maximum number of retries, default is `30`.",
+						Validators: []validator.Int64{
+							int64validator.AtLeast(0),
+						},
+					},
+
+					"min_backoff": schema.StringAttribute{
+						Optional:    true,
+						Description: "The minimum duration to wait between retries, e.g. `500ms`, default is `1s`.",
+					},
+
+					"max_backoff": schema.StringAttribute{
+						Optional:    true,
+						Description: "The maximum duration to wait between retries, default is `30s`.",
+					},
+
+					"jitter": schema.BoolAttribute{
+						Optional:    true,
+						Description: "Randomize the duration to wait between retries, default is `true`.",
+					},
+
+					"status_codes": schema.ListAttribute{
+						Optional:    true,
+						ElementType: types.Int64Type,
+						Description: "The HTTP status codes to retry, default is `[429, 500, 503, 504]`.",
+					},
+				},
+			},
 		},
 	}
 }
@@ -0,0 +1,182 @@
+package provider
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"net/http"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/GoodCloudWorks/terraform-provider-msgraph/msgraph/client"
+	"github.com/go-resty/resty/v2"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+const (
+	defaultMaxRetries = 30
+	defaultMinBackoff = 1 * time.Second
+	defaultMaxBackoff = 30 * time.Second
+
+	// Retry-After values are honored as is, so resty must never clamp them.
+	unboundedRetryWaitTime = 24 * time.Hour
+)
+
+var defaultRetryStatusCodes = []int{
+	http.StatusTooManyRequests,
+	http.StatusInternalServerError,
+	http.StatusServiceUnavailable,
+	http.StatusGatewayTimeout,
+}
+
+type MsGraphProviderRetryData struct {
+	MaxRetries  types.Int64  `tfsdk:"max_retries"`
+	MinBackoff  types.String `tfsdk:"min_backoff"`
+	MaxBackoff  types.String `tfsdk:"max_backoff"`
+	Jitter      types.Bool   `tfsdk:"jitter"`
+	StatusCodes types.List   `tfsdk:"status_codes"`
+}
+
+type retryPolicy struct {
+	maxRetries  int
+	minBackoff  time.Duration
+	maxBackoff  time.Duration
+	jitter      bool
+	statusCodes []int
+}
+
+func (data *MsGraphProviderRetryData) retryPolicy() (*retryPolicy, error) {
+	policy := &retryPolicy{
+		maxRetries:  defaultMaxRetries,
+		minBackoff:  defaultMinBackoff,
+		maxBackoff:  defaultMaxBackoff,
+		jitter:      true,
+		statusCodes: defaultRetryStatusCodes,
+	}
+
+	if data == nil {
+		return policy, nil
+	}
+
+	if !data.MaxRetries.IsNull() {
+		policy.maxRetries = int(data.MaxRetries.ValueInt64())
+	}
+
+	if !data.MinBackoff.IsNull() {
+		value, err := time.ParseDuration(data.MinBackoff.ValueString())
+		if err != nil {
+			return nil, fmt.Errorf("invalid retry min_backoff: %w", err)
+		}
+		policy.minBackoff = value
+	}
+
+	if !data.MaxBackoff.IsNull() {
+		value, err := time.ParseDuration(data.MaxBackoff.ValueString())
+		if err != nil {
+			return nil, fmt.Errorf("invalid retry max_backoff: %w", err)
+		}
+		policy.maxBackoff = value
+	}
+
+	if policy.maxBackoff < policy.minBackoff {
+		return nil, fmt.Errorf("retry max_backoff %s is less than min_backoff %s", policy.maxBackoff, policy.minBackoff)
+	}
+
+	if !data.Jitter.IsNull() {
+		policy.jitter = data.Jitter.ValueBool()
+	}
+
+	if !data.StatusCodes.IsNull() {
+		policy.statusCodes = nil
+		for _, statusCode := range data.StatusCodes.Elements() {
+			policy.statusCodes = append(policy.statusCodes, int(statusCode.(types.Int64).ValueInt64()))
+		}
+	}
+
+	return policy, nil
+}
+
+func (policy *retryPolicy) apply(client *resty.Client) {
+	client.SetRetryCount(policy.maxRetries)
+	client.SetRetryWaitTime(0)
+	client.SetRetryMaxWaitTime(unboundedRetryWaitTime)
+	client.SetRetryAfter(policy.retryAfter)
+	client.AddRetryCondition(policy.shouldRetry)
+}
+
+// shouldRetry retries idempotent requests on the configured status codes and
+// transport errors. Non-idempotent requests are only retried when throttled,
+// since the request was then not processed, or when the request context
+// carries a probe that proves that retrying is safe.
+func (policy *retryPolicy) shouldRetry(response *resty.Response, err error) bool {
+	if response == nil || response.Request == nil {
+		return false
+	}
+
+	if err == nil && !slices.Contains(policy.statusCodes, response.StatusCode()) {
+		return false
+	}
+
+	if err == nil && response.StatusCode() == http.StatusTooManyRequests {
+		return true
+	}
+
+	if isIdempotent(response.Request.Method) {
+		return true
+	}
+
+	ctx := response.Request.Context()
+	if probe, ok := client.RetryProbe(ctx); ok {
+		return probe(ctx)
+	}
+
+	return false
+}
+
+// retryAfter honors the Retry-After and x-ms-retry-after-ms headers exactly,
+// and otherwise backs off exponentially between min_backoff and max_backoff.
+func (policy *retryPolicy) retryAfter(_ *resty.Client, response *resty.Response) (time.Duration, error) {
+	if wait, ok := retryAfterFromHeaders(response.Header()); ok {
+		return wait, nil
+	}
+
+	return policy.backoff(response.Request.Attempt), nil
+}
+
+func (policy *retryPolicy) backoff(attempt int) time.Duration {
+	backoff := float64(policy.minBackoff) * math.Exp2(float64(attempt-1))
+	wait := time.Duration(math.Min(backoff, float64(policy.maxBackoff)))
+
+	if policy.jitter && wait > policy.minBackoff {
+		wait = policy.minBackoff + time.Duration(rand.Int63n(int64(wait-policy.minBackoff)+1))
+	}
+
+	return max(wait, time.Nanosecond)
+}
+
+func retryAfterFromHeaders(header http.Header) (time.Duration, bool) {
+	if value := header.Get("x-ms-retry-after-ms"); value != "" {
+		if milliseconds, err := strconv.ParseInt(value, 10, 64); err == nil && milliseconds >= 0 {
+			return max(time.Duration(milliseconds)*time.Millisecond, time.Nanosecond), true
+		}
+	}
+
+	if value := header.Get("Retry-After"); value != "" {
+		if seconds, err := strconv.ParseInt(value, 10, 64); err == nil && seconds >= 0 {
+			return max(time.Duration(seconds)*time.Second, time.Nanosecond), true
+		}
+		if date, err := http.ParseTime(value); err == nil {
+			return max(time.Until(date), time.Nanosecond), true
+		}
+	}
+
+	return 0, false
+}
+
+// isIdempotent reports whether a request can be safely repeated. Microsoft
+// Graph applies PATCH requests as property updates, so only POST requests,
+// which create objects or invoke actions, are considered non-idempotent.
+func isIdempotent(method string) bool {
+	return method != http.MethodPost
+}
@@ -0,0 +1,94 @@
+package provider
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/GoodCloudWorks/terraform-provider-msgraph/msgraph/client"
+	"github.com/go-resty/resty/v2"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/stretchr/testify/require"
+)
+
+func retryResponse(ctx context.Context, method string, statusCode int, header http.Header) *resty.Response {
+	request := resty.New().R().SetContext(ctx)
+	request.Method = method
+	request.Attempt = 1
+
+	return &resty.Response{
+		Request: request,
+		RawResponse: &http.Response{
+			StatusCode: statusCode,
+			Header:     header,
+		},
+	}
+}
+
+func TestRetryPolicy(t *testing.T) {
+	policy, err := (*MsGraphProviderRetryData)(nil).retryPolicy()
+	require.NoError(t, err)
+
+	t.Run("when GET fails with a server error then it is retried", func(t *testing.T) {
+		require.True(t, policy.shouldRetry(retryResponse(context.Background(), http.MethodGet, http.StatusServiceUnavailable, nil), nil))
+	})
+
+	t.Run("when status code is not configured then it is not retried", func(t *testing.T) {
+		require.False(t, policy.shouldRetry(retryResponse(context.Background(), http.MethodGet, http.StatusBadRequest, nil), nil))
+	})
+
+	t.Run("when POST fails with a server error then it is not retried", func(t *testing.T) {
+		require.False(t, policy.shouldRetry(retryResponse(context.Background(), http.MethodPost, http.StatusServiceUnavailable, nil), nil))
+	})
+
+	t.Run("when POST is throttled then it is retried", func(t *testing.T) {
+		require.True(t, policy.shouldRetry(retryResponse(context.Background(), http.MethodPost, http.StatusTooManyRequests, nil), nil))
+	})
+
+	t.Run("when POST fails and the probe allows it then it is retried", func(t *testing.T) {
+		ctx := client.WithRetryProbe(context.Background(), func(context.Context) bool { return true })
+		require.True(t, policy.shouldRetry(retryResponse(ctx, http.MethodPost, http.StatusInternalServerError, nil), nil))
+
+		ctx = client.WithRetryProbe(context.Background(), func(context.Context) bool { return false })
+		require.False(t, policy.shouldRetry(retryResponse(ctx, http.MethodPost, http.StatusInternalServerError, nil), nil))
+	})
+
+	t.Run("when Retry-After is present then it is honored exactly", func(t *testing.T) {
+		wait, err := policy.retryAfter(nil, retryResponse(context.Background(), http.MethodGet, http.StatusTooManyRequests, http.Header{"Retry-After": []string{"120"}}))
+		require.NoError(t, err)
+		require.Equal(t, 120*time.Second, wait)
+	})
+
+	t.Run("when x-ms-retry-after-ms is present then it takes precedence", func(t *testing.T) {
+		header := http.Header{}
+		header.Set("Retry-After", "10")
+		header.Set("x-ms-retry-after-ms", "250")
+
+		wait, err := policy.retryAfter(nil, retryResponse(context.Background(), http.MethodGet, http.StatusTooManyRequests, header))
+		require.NoError(t, err)
+		require.Equal(t, 250*time.Millisecond, wait)
+	})
+}
+
+func TestRetryPolicyBackoff(t *testing.T) {
+	data := &MsGraphProviderRetryData{
+		MaxRetries:  types.Int64Value(5),
+		MinBackoff:  types.StringValue("100ms"),
+		MaxBackoff:  types.StringValue("1s"),
+		Jitter:      types.BoolValue(false),
+		StatusCodes: types.ListNull(types.Int64Type),
+	}
+
+	policy, err := data.retryPolicy()
+	require.NoError(t, err)
+
+	require.Equal(t, 100*time.Millisecond, policy.backoff(1))
+	require.Equal(t, 200*time.Millisecond, policy.backoff(2))
+	require.Equal(t, 800*time.Millisecond, policy.backoff(4))
+	require.Equal(t, 1*time.Second, policy.backoff(10))
+
+	data.MaxBackoff = types.StringValue("10ms")
+	_, err = data.retryPolicy()
+	require.Error(t, err)
+}
@@ -1,11 +1,18 @@
 package msgraph
 
 import (
+	"context"
 	"fmt"
+	"testing"
 
+	"github.com/GoodCloudWorks/terraform-provider-msgraph/msgraph/client"
+	"github.com/GoodCloudWorks/terraform-provider-msgraph/msgraph/id"
 	msgraphprovider "github.com/GoodCloudWorks/terraform-provider-msgraph/msgraph/provider"
 	"github.com/hashicorp/terraform-plugin-framework/providerserver"
+	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/terraform"
 )
 
 var protoV6ProviderFactories = map[string]func() (tfprotov6.ProviderServer, error){
@@ -24,3 +31,58 @@ func defaultProviderConfigWith(config string, params ...any) string {
 		%s
 		`, defaultProviderConfig(), fmt.Sprintf(config, params...))
 }
+
+func testAccNewClient() (client.MsGraphClient, error) {
+	data := msgraphprovider.MsGraphProviderData{}
+	if diags := data.Configure(); diags.HasError() {
+		return nil, fmt.Errorf("failed to configure client: %v", diags)
+	}
+
+	return data.NewClient()
+}
+
+// testAccDeleteObject deletes an object outside of Terraform.
+func testAccDeleteObject(t *testing.T, path string) {
+	client, err := testAccNewClient()
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+
+	response, err := delete(client.R(context.Background(), types.StringNull()), path)
+	if err != nil || response.IsError() {
+		t.Fatalf("failed to delete %q: %v %s", path, err, response.Body())
+	}
+}
+
+// testAccCheckNotSoftDeleted checks that the object with the ID in `objectPath`
+// is not in the deleted items.
+func testAccCheckNotSoftDeleted(objectPath *string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		client, err := testAccNewClient()
+		if err != nil {
+			return err
+		}
+
+		objectID, err := id.Parse(*objectPath)
+		if err != nil {
+			return err
+		}
+
+		if isSoftDeleted(context.Background(), client, types.StringNull(), objectID.ObjectId()) {
+			return fmt.Errorf("object %q is still in %q", objectID.ObjectId(), deletedItemsCollection)
+		}
+		return nil
+	}
+}
+
+// testAccExtractResourceAttr stores the value of a resource attribute in `value`.
+func testAccExtractResourceAttr(resourceName string, attribute string, value *string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[resourceName]
+		if !ok {
+			return fmt.Errorf("resource not found: %s", resourceName)
+		}
+		*value = rs.Primary.Attributes[attribute]
+		return nil
+	}
+}
@@ -0,0 +1,177 @@
+package msgraph
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/GoodCloudWorks/terraform-provider-msgraph/msgraph/client"
+	"github.com/GoodCloudWorks/terraform-provider-msgraph/msgraph/id"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+const (
+	defaultReferenceTargetCollection = "directoryObjects"
+
+	// Microsoft Graph accepts at most 20 links in a single @odata.bind request.
+	maxReferencesPerRequest = 20
+)
+
+type referenceClient struct {
+	client           client.MsGraphClient
+	apiVersion       types.String
+	object           string
+	navigation       string
+	targetCollection string
+}
+
+func newReferenceClient(client client.MsGraphClient, apiVersion types.String, object string, navigation string, targetCollection string) *referenceClient {
+	return &referenceClient{
+		client:           client,
+		apiVersion:       apiVersion,
+		object:           strings.Trim(object, "/"),
+		navigation:       strings.Trim(navigation, "/"),
+		targetCollection: targetCollection,
+	}
+}
+
+func (r *referenceClient) navigationPath() string {
+	return r.object + "/" + r.navigation
+}
+
+func (r *referenceClient) odataID(targetID string) string {
+	return r.client.BaseURL(r.apiVersion) + id.New(r.targetCollection, targetID).Path
+}
+
+// exists reports whether the link to `targetID` exists, without listing the
+// whole navigation collection.
+func (r *referenceClient) exists(ctx context.Context, targetID string) (bool, diag.Diagnostics) {
+	http := r.client.R(ctx, r.apiVersion).SetQueryParam("$select", "id")
+
+	_, found, diags := ensureFindObjectAsDynamic(http, id.New(r.navigationPath(), targetID).Path)
+	return found, diags
+}
+
+func (r *referenceClient) list(ctx context.Context) ([]string, diag.Diagnostics) {
+	return ensureListObjectIDs(r.client.R(ctx, r.apiVersion), r.navigationPath(), 0)
+}
+
+func (r *referenceClient) add(ctx context.Context, targetID string) diag.Diagnostics {
+	// Retrying is only safe as long as the link has not been created.
+	ctx = client.WithRetryProbe(ctx, func(ctx context.Context) bool {
+		return isConsistentlyAbsent(ctx, func(ctx context.Context) (bool, diag.Diagnostics) {
+			return r.exists(ctx, targetID)
+		})
+	})
+
+	http := r.client.R(ctx, r.apiVersion)
+
+	diags := ensureRequestSetBodyFromMap(http, map[string]interface{}{
+		"@odata.id": r.odataID(targetID),
+	})
+	if diags.HasError() {
+		return diags
+	}
+
+	response, err := post(http, r.navigationPath()+"/$ref")
+	return ensureHttpResponseSucceeded(response, err)
+}
+
+func (r *referenceClient) addAll(ctx context.Context, targetIDs []string) diag.Diagnostics {
+	for start := 0; start < len(targetIDs); start += maxReferencesPerRequest {
+		end := min(start+maxReferencesPerRequest, len(targetIDs))
+
+		odataIDs := make([]string, 0, end-start)
+		for _, targetID := range targetIDs[start:end] {
+			odataIDs = append(odataIDs, r.odataID(targetID))
+		}
+
+		http := r.client.R(ctx, r.apiVersion)
+
+		diags := ensureRequestSetBodyFromMap(http, map[string]interface{}{
+			r.navigation + "@odata.bind": odataIDs,
+		})
+		if diags.HasError() {
+			return diags
+		}
+
+		response, err := patch(http, r.object)
+		if diags := ensureHttpResponseSucceeded(response, err); diags.HasError() {
+			return diags
+		}
+	}
+
+	return noErrors()
+}
+
+func (r *referenceClient) remove(ctx context.Context, targetID string) diag.Diagnostics {
+	http := r.client.R(ctx, r.apiVersion)
+
+	response, err := delete(http, id.New(r.navigationPath(), targetID).Path+"/$ref")
+	if err == nil && response.StatusCode() == httpStatusNotFound {
+		return noErrors()
+	}
+
+	return ensureHttpResponseSucceeded(response, err)
+}
+
+func (r *referenceClient) removeAll(ctx context.Context, targetIDs []string) diag.Diagnostics {
+	for _, targetID := range targetIDs {
+		if diags := r.remove(ctx, targetID); diags.HasError() {
+			return diags
+		}
+	}
+
+	return noErrors()
+}
+
+type referenceID struct {
+	object     string
+	navigation string
+	targetID   string
+	apiVersion string
+}
+
+func ensureParseReferenceID(value string) (*referenceID, diag.Diagnostics) {
+	id, diags := ensureParseID(value)
+	if diags.HasError() {
+		return nil, diags
+	}
+
+	collection := id.Collection()
+	index := strings.LastIndex(collection, "/")
+	if index == -1 {
+		return nil, errorDiagnostics(fmt.Sprintf("Failed to parse reference ID: %q", value), "Expected format: <object>/<navigation>/<target_id>")
+	}
+
+	return &referenceID{
+		object:     collection[:index],
+		navigation: collection[index+1:],
+		targetID:   id.ObjectId(),
+		apiVersion: id.ApiVersion(),
+	}, noErrors()
+}
+
+func diffReferences(current []string, desired []string) (toAdd []string, toRemove []string) {
+	currentSet := make(map[string]bool, len(current))
+	for _, v := range current {
+		currentSet[v] = true
+	}
+
+	desiredSet := make(map[string]bool, len(desired))
+	for _, v := range desired {
+		desiredSet[v] = true
+		if !currentSet[v] {
+			toAdd = append(toAdd, v)
+		}
+	}
+
+	for _, v := range current {
+		if !desiredSet[v] {
+			toRemove = append(toRemove, v)
+		}
+	}
+
+	return toAdd, toRemove
+}
@@ -0,0 +1,253 @@
+package msgraph
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/GoodCloudWorks/terraform-provider-msgraph/msgraph/client"
+	"github.com/GoodCloudWorks/terraform-provider-msgraph/msgraph/dynamic"
+	"github.com/google/uuid"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/dynamicplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/mapplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+var (
+	_ resource.Resource              = &msGraphActionResource{}
+	_ resource.ResourceWithConfigure = &msGraphActionResource{}
+)
+
+type msGraphActionResource struct {
+	client client.MsGraphClient
+}
+
+type msGraphActionResourceModel struct {
+	ID                types.String  `tfsdk:"id"`
+	Action            types.String  `tfsdk:"action"`
+	ApiVersion        types.String  `tfsdk:"api_version"`
+	Body              types.Dynamic `tfsdk:"body"`
+	Triggers          types.Map     `tfsdk:"triggers"`
+	UndoAction        types.String  `tfsdk:"undo_action"`
+	UndoBody          types.Dynamic `tfsdk:"undo_body"`
+	UndoOutputMapping types.Map     `tfsdk:"undo_output_mapping"`
+	Output            types.Dynamic `tfsdk:"output"`
+}
+
+func NewMsGraphActionResource() resource.Resource {
+	return &msGraphActionResource{}
+}
+
+func (r *msGraphActionResource) Configure(_ context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	if v, ok := req.ProviderData.(client.MsGraphClient); ok {
+		r.client = v
+	}
+}
+
+func (*msGraphActionResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_action"
+}
+
+func (*msGraphActionResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "This resource provides the ability to invoke a Microsoft Graph action, such as `addPassword`, and optionally invoke an undo action, such as `removePassword`, when it is destroyed.",
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Computed:    true,
+				Description: "The ID of the action invocation.",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+
+			"action": schema.StringAttribute{
+				Required:    true,
+				Description: "The path of the action to invoke, e.g. `applications/{id}/addPassword`.",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+
+			"api_version": schema.StringAttribute{
+				Optional:    true,
+				Description: "Override the provider Microsoft Graph API version.",
+			},
+
+			"body": schema.DynamicAttribute{
+				Optional:    true,
+				Description: "The body to send to the action. Unlike `msgraph_object`, the order of the elements of arrays is significant.",
+				PlanModifiers: []planmodifier.Dynamic{
+					dynamic.UseStateWhen(dynamic.SemanticallyEqual),
+					dynamicplanmodifier.RequiresReplace(),
+					dynamic.KnownWhereConfigured(),
+				},
+			},
+
+			"triggers": schema.MapAttribute{
+				Optional:    true,
+				ElementType: types.StringType,
+				Description: "Arbitrary values that, when changed, invoke the undo action and then the action again.",
+				PlanModifiers: []planmodifier.Map{
+					mapplanmodifier.RequiresReplace(),
+				},
+			},
+
+			"undo_action": schema.StringAttribute{
+				Optional:    true,
+				Description: "The path of the action to invoke when the resource is destroyed, e.g. `applications/{id}/removePassword`.",
+			},
+
+			"undo_body": schema.DynamicAttribute{
+				Optional:    true,
+				Description: "The body to send to the undo action.",
+			},
+
+			"undo_output_mapping": schema.MapAttribute{
+				Optional:    true,
+				ElementType: types.StringType,
+				Description: "A map of undo body properties to dot separated paths in `output` whose values are added to the undo body, e.g. `{ keyId = \"keyId\" }`.",
+			},
+
+			"output": schema.DynamicAttribute{
+				Computed:    true,
+				Sensitive:   true,
+				Description: "The response returned by the action.",
+				PlanModifiers: []planmodifier.Dynamic{
+					dynamicplanmodifier.UseStateForUnknown(),
+				},
+			},
+		},
+	}
+}
+
+func (r *msGraphActionResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var model msGraphActionResourceModel
+	diags := req.Plan.Get(ctx, &model)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	path, diags := ensureIsValidPathString(model.Action)
+	if diags.HasError() {
+		resp.Diagnostics.Append(diags...)
+		return
+	}
+
+	http := r.client.R(ctx, model.ApiVersion)
+
+	if !model.Body.IsNull() {
+		resp.Diagnostics.Append(ensureRequestSetBodyFromDynamic(http, model.Body)...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+	}
+
+	response, err := post(http, path)
+	resp.Diagnostics.Append(ensureHttpResponseSucceeded(response, err)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	content, diags := ensureResponseAsDynamic(response)
+	if diags.HasError() {
+		resp.Diagnostics.Append(diags...)
+		return
+	}
+
+	model.ID = types.StringValue(uuid.NewString())
+	model.Output = content
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, model)...)
+}
+
+func (r *msGraphActionResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var model msGraphActionResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &model)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, model)...)
+}
+
+func (r *msGraphActionResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var model msGraphActionResourceModel
+	diags := req.Plan.Get(ctx, &model)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, model)...)
+}
+
+func (r *msGraphActionResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var model msGraphActionResourceModel
+	diags := req.State.Get(ctx, &model)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if model.UndoAction.IsNull() {
+		return
+	}
+
+	path, diags := ensureIsValidPathString(model.UndoAction)
+	if diags.HasError() {
+		resp.Diagnostics.Append(diags...)
+		return
+	}
+
+	body, diags := ensureUndoBody(model)
+	if diags.HasError() {
+		resp.Diagnostics.Append(diags...)
+		return
+	}
+
+	http := r.client.R(ctx, model.ApiVersion)
+	setRequestJSONBody(http, body)
+
+	response, err := post(http, path)
+	if err == nil && response.StatusCode() == httpStatusNotFound {
+		return
+	}
+
+	resp.Diagnostics.Append(ensureHttpResponseSucceeded(response, err)...)
+}
+
+// ensureUndoBody merges `undo_body` with the values `undo_output_mapping`
+// selects from the response of the action.
+func ensureUndoBody(model msGraphActionResourceModel) ([]byte, diag.Diagnostics) {
+	body, diags := ensureDynamicAsMap(model.UndoBody)
+	if diags.HasError() {
+		return nil, diags
+	}
+
+	if len(model.UndoOutputMapping.Elements()) > 0 {
+		output, err := dynamic.ToJSON(model.Output)
+		if err != nil {
+			return nil, errorDiagnostics("Failed to marshal output to JSON.", err.Error())
+		}
+
+		for key, path := range model.UndoOutputMapping.Elements() {
+			value, diags := ensureLookupJSONPath(output, path.(types.String).ValueString())
+			if diags.HasError() {
+				return nil, diags
+			}
+			body[key] = value
+		}
+	}
+
+	result, err := json.Marshal(body)
+	if err != nil {
+		return nil, errorDiagnostics("Failed to marshal request body to JSON.", err.Error())
+	}
+
+	return result, noErrors()
+}
@@ -0,0 +1,77 @@
+package msgraph
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/acctest"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/plancheck"
+)
+
+func TestAccMsGraphActionResource(t *testing.T) {
+	const resourceName = "msgraph_action.password"
+	applicationName := acctest.RandString(10)
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: protoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: msGraphActionResourceConfig(applicationName, "1"),
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectResourceAction(resourceName, plancheck.ResourceActionCreate),
+					},
+				},
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttrSet(resourceName, "id"),
+					resource.TestCheckResourceAttrSet(resourceName, "output.keyId"),
+					resource.TestCheckResourceAttrSet(resourceName, "output.secretText"),
+				),
+			},
+			{
+				Config: msGraphActionResourceConfig(applicationName, "1"),
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectResourceAction(resourceName, plancheck.ResourceActionNoop),
+					},
+				},
+			},
+			{
+				Config: msGraphActionResourceConfig(applicationName, "2"),
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectResourceAction(resourceName, plancheck.ResourceActionDestroyBeforeCreate),
+					},
+				},
+			},
+		},
+	})
+}
+
+func msGraphActionResourceConfig(applicationName string, rotation string) string {
+	return defaultProviderConfigWith(`
+	resource "msgraph_object" "application" {
+		collection = "applications"
+		properties = {
+			displayName = "%[1]s"
+		}
+	}
+
+	resource "msgraph_action" "password" {
+		action = "${msgraph_object.application.id}/addPassword"
+		body = {
+			passwordCredential = {
+				displayName = "%[1]s"
+			}
+		}
+		triggers = {
+			rotation = "%[2]s"
+		}
+
+		undo_action = "${msgraph_object.application.id}/removePassword"
+		undo_output_mapping = {
+			keyId = "keyId"
+		}
+	}
+	`, applicationName, rotation)
+}
@@ -2,33 +2,76 @@ package msgraph
 
 import (
 	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"slices"
 
 	"github.com/GoodCloudWorks/terraform-provider-msgraph/msgraph/client"
 	"github.com/GoodCloudWorks/terraform-provider-msgraph/msgraph/dynamic"
 	"github.com/GoodCloudWorks/terraform-provider-msgraph/msgraph/id"
+	"github.com/go-resty/resty/v2"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/booldefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/dynamicplanmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringdefault"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 )
 
 var (
-	_ resource.Resource                = &msGraphObjectResource{}
-	_ resource.ResourceWithConfigure   = &msGraphObjectResource{}
-	_ resource.ResourceWithImportState = &msGraphObjectResource{}
+	_ resource.Resource                   = &msGraphObjectResource{}
+	_ resource.ResourceWithConfigure      = &msGraphObjectResource{}
+	_ resource.ResourceWithImportState    = &msGraphObjectResource{}
+	_ resource.ResourceWithValidateConfig = &msGraphObjectResource{}
 )
 
 type msGraphObjectResource struct {
 	client client.MsGraphClient
 }
 
+const (
+	destroyBehaviorDelete          = "delete"
+	destroyBehaviorPermanentDelete = "permanent_delete"
+	destroyBehaviorAbandon         = "abandon"
+
+	createModePost   = "post"
+	createModeUpsert = "upsert"
+	createModeAdopt  = "adopt"
+
+	// The keys of the configured properties, so that properties removed from
+	// the configuration can be cleared. Imported objects have none.
+	privateStateConfiguredProperties = "configured_properties"
+)
+
 type msGraphObjectResourceModel struct {
-	Collection types.String  `tfsdk:"collection"`
-	ID         types.String  `tfsdk:"id"`
-	ApiVersion types.String  `tfsdk:"api_version"`
-	Properties types.Dynamic `tfsdk:"properties"`
-	Output     types.Dynamic `tfsdk:"output"`
+	Collection                 types.String  `tfsdk:"collection"`
+	ID                         types.String  `tfsdk:"id"`
+	ApiVersion                 types.String  `tfsdk:"api_version"`
+	Properties                 types.Dynamic `tfsdk:"properties"`
+	CreateOnlyProperties       types.Dynamic `tfsdk:"create_only_properties"`
+	ReplaceOnCreateOnlyChanges types.Bool    `tfsdk:"replace_on_create_only_changes"`
+	DestroyBehavior            types.String  `tfsdk:"destroy_behavior"`
+	RestoreIfDeleted           types.Bool    `tfsdk:"restore_if_deleted"`
+	DeletedItemType            types.String  `tfsdk:"deleted_item_type"`
+	MatchFilter                types.String  `tfsdk:"match_filter"`
+	RetryCreateOnServerError   types.Bool    `tfsdk:"retry_create_on_server_error"`
+	CreateMode                 types.String  `tfsdk:"create_mode"`
+	AlternateKey               types.String  `tfsdk:"alternate_key"`
+	ClearRemovedProperties     types.Bool    `tfsdk:"clear_removed_properties"`
+	WriteOnlyProperties        types.List    `tfsdk:"write_only_properties"`
+	IgnoreChangesPaths         types.List    `tfsdk:"ignore_changes_paths"`
+	ReplaceTriggersPaths       types.List    `tfsdk:"replace_triggers_paths"`
+	UnorderedArrayPaths        types.List    `tfsdk:"unordered_array_paths"`
+	PropertyNormalizers        types.Map     `tfsdk:"property_normalizers"`
+	ArrayKeys                  types.Map     `tfsdk:"array_keys"`
+	Output                     types.Dynamic `tfsdk:"output"`
 }
 
 func NewMsGraphObjectResource() resource.Resource {
@@ -72,15 +115,135 @@ func (*msGraphObjectResource) Schema(_ context.Context, _ resource.SchemaRequest
 
 			"properties": schema.DynamicAttribute{
 				Required:    true,
-				Description: "The properties of the object.",
+				Description: "The properties of the object, either as an object or as a JSON string, e.g. `jsonencode(...)` or `file(\"app.json\")`. A JSON string is stored as a canonical JSON string.",
+				PlanModifiers: []planmodifier.Dynamic{
+					dynamic.UseStateWhenWithOptions(dynamic.SemanticallyEqual, objectPlanOptions),
+					dynamic.KnownWhereConfigured(),
+				},
+			},
+
+			"ignore_changes_paths": schema.ListAttribute{
+				Optional:    true,
+				ElementType: types.StringType,
+				Description: "The JSON pointers of the properties whose changes are ignored, e.g. `/web/redirectUris`. Use `*` to match every array element. Ignored changes are only hidden from the plan when the rest of the properties is unchanged; otherwise the plan shows them, although they are still not sent to Microsoft Graph.",
+			},
+
+			"replace_triggers_paths": schema.ListAttribute{
+				Optional:    true,
+				ElementType: types.StringType,
+				Description: "The JSON pointers of the properties whose changes replace the object, e.g. `/signInAudience`. Use `*` to match every array element.",
+			},
+
+			"unordered_array_paths": schema.ListAttribute{
+				Optional:    true,
+				ElementType: types.StringType,
+				Description: "The JSON pointers of the arrays of objects whose order is not significant, e.g. `/api/oauth2PermissionScopes`. Use `*` to match every array element. Arrays of primitives are always compared regardless of their order.",
+			},
+
+			"array_keys": schema.MapAttribute{
+				Optional:    true,
+				ElementType: types.StringType,
+				Description: "The properties that identify the elements of arrays of objects, by JSON pointer of the array, e.g. `{\"/api/oauth2PermissionScopes\" = \"value\"}`. Use `*` to match every array element. Elements are matched by key when comparing and refreshing arrays, regardless of their order. Arrays of objects that all have an `id`, `keyId` or `resourceAppId` are matched by that key by default.",
+			},
+
+			"property_normalizers": schema.MapAttribute{
+				Optional:    true,
+				ElementType: types.StringType,
+				Description: "The normalizers applied when comparing properties, by JSON pointer, e.g. `{\"/mail\" = \"case_insensitive\"}`. Use `*` to match every array element. Possible values are `case_insensitive`, `datetime`, `empty_as_null`, `guid` and `space_delimited`. The `datetime` and `guid` normalizers are always applied.",
+			},
+
+			"clear_removed_properties": schema.BoolAttribute{
+				Optional:    true,
+				Computed:    true,
+				Default:     booldefault.StaticBool(true),
+				Description: "Set properties removed from `properties` to `null` on update, instead of leaving their current value in Microsoft Graph. The properties of an imported object are only cleared once they have been applied by an update. Default is `true`.",
+			},
+
+			"write_only_properties": schema.ListAttribute{
+				Optional:    true,
+				ElementType: types.StringType,
+				Description: "The JSON pointers of the properties that Microsoft Graph never returns, e.g. `/passwordProfile`, whose configured value is kept on refresh instead of being reported as drift. `@odata.bind` links are always kept.",
+			},
+
+			"destroy_behavior": schema.StringAttribute{
+				Optional:    true,
+				Computed:    true,
+				Default:     stringdefault.StaticString(destroyBehaviorDelete),
+				Description: "What to do with the object on destroy. `delete` deletes the object, which only soft-deletes directory objects such as applications and groups. `permanent_delete` also permanently deletes soft-deleted objects from `directory/deletedItems`, waiting up to two minutes for them to show up there. `abandon` only removes the object from the state. Default is `delete`.",
+				Validators: []validator.String{
+					stringvalidator.OneOf(destroyBehaviorDelete, destroyBehaviorPermanentDelete, destroyBehaviorAbandon),
+				},
+			},
+
+			"restore_if_deleted": schema.BoolAttribute{
+				Optional:    true,
+				Computed:    true,
+				Default:     booldefault.StaticBool(false),
+				Description: "Restore the soft-deleted object matching `match_filter` from `directory/deletedItems` on create, and apply the properties to it, instead of creating a new object. Default is `false`.",
+			},
+
+			"deleted_item_type": schema.StringAttribute{
+				Optional:    true,
+				Description: "The type of the object in `directory/deletedItems`, e.g. `group`. Required with `restore_if_deleted` unless the collection is one of `administrativeUnits`, `applications`, `groups`, `servicePrincipals` or `users`.",
+			},
+
+			"match_filter": schema.StringAttribute{
+				Optional:    true,
+				Description: "The OData `$filter` expression that finds an existing object equivalent to this one, e.g. `uniqueName eq 'my-app'`. Microsoft Graph reads are eventually consistent, so an object created moments ago may not be found yet.",
+			},
+
+			"retry_create_on_server_error": schema.BoolAttribute{
+				Optional:    true,
+				Computed:    true,
+				Default:     booldefault.StaticBool(false),
+				Description: "Retry the creation when Microsoft Graph responds with a server error, once several reads spread over a few seconds found no object matching `match_filter`. The object created by the failed request may still be missed and duplicated, as reads are eventually consistent. Default is `false`.",
+			},
+
+			"create_mode": schema.StringAttribute{
+				Optional:    true,
+				Computed:    true,
+				Default:     stringdefault.StaticString(createModePost),
+				Description: "How to create the object. `post` creates the object in the collection. `upsert` creates or updates the object identified by `alternate_key`. `adopt` takes ownership of the existing object matching `match_filter` when the collection reports a conflict, and applies the properties that differ to it. Default is `post`.",
+				Validators: []validator.String{
+					stringvalidator.OneOf(createModePost, createModeUpsert, createModeAdopt),
+				},
+			},
+
+			"alternate_key": schema.StringAttribute{
+				Optional:    true,
+				Description: "The alternate key identifying the object when `create_mode` is `upsert`, e.g. `uniqueName='my-app'`.",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+
+			"create_only_properties": schema.DynamicAttribute{
+				Optional:    true,
+				Description: "The properties that are only sent when the object is created, e.g. `owners@odata.bind`. They are merged into `properties`, never sent on update and never compared with the object in Microsoft Graph. They cannot be used when `create_mode` is `upsert`, which does not know whether the object is created.",
 				PlanModifiers: []planmodifier.Dynamic{
 					dynamic.UseStateWhen(dynamic.SemanticallyEqual),
+					dynamicplanmodifier.RequiresReplaceIf(
+						requiresReplaceIfAttributeIsTrue("replace_on_create_only_changes"),
+						"Replace the object when `replace_on_create_only_changes` is true.",
+						"Replace the object when `replace_on_create_only_changes` is true.",
+					),
+					dynamic.KnownWhereConfigured(),
 				},
 			},
 
+			"replace_on_create_only_changes": schema.BoolAttribute{
+				Optional:    true,
+				Computed:    true,
+				Default:     booldefault.StaticBool(false),
+				Description: "Replace the object when `create_only_properties` changes. Default is `false`.",
+			},
+
 			"output": schema.DynamicAttribute{
 				Computed:    true,
-				Description: "The object retrieved from Microsoft Graph.",
+				Description: "The object retrieved from Microsoft Graph. It is only unknown during plan when the object is created or its properties change.",
+				PlanModifiers: []planmodifier.Dynamic{
+					outputPlanModifier{},
+				},
 			},
 		},
 	}
@@ -100,38 +263,250 @@ func (r *msGraphObjectResource) Create(ctx context.Context, req resource.CreateR
 		return
 	}
 
+	var objectID string
+	restored := false
+	if model.RestoreIfDeleted.ValueBool() {
+		objectID, restored, diags = r.restoreDeleted(ctx, model, path)
+		if diags.HasError() {
+			resp.Diagnostics.Append(diags...)
+			return
+		}
+	}
+
+	if !restored {
+		switch model.CreateMode.ValueString() {
+		case createModeUpsert:
+			objectID, diags = r.upsert(ctx, model, path)
+		case createModeAdopt:
+			objectID, diags = r.adopt(ctx, model, path)
+		default:
+			objectID, diags = r.post(ctx, model, path)
+		}
+		if diags.HasError() {
+			resp.Diagnostics.Append(diags...)
+			return
+		}
+	}
+
+	id := id.New(path, objectID)
+	model.ID = id.AsString()
+
+	// The planned output is kept when it is known, as nothing that affects it
+	// changed.
+	if model.Output.IsUnknown() {
+		content, diags := ensureGetObjectAsDynamic(r.client.R(ctx, model.ApiVersion), id.Path)
+		if diags.HasError() {
+			resp.Diagnostics.Append(diags...)
+			return
+		}
+		model.Output = content
+	}
+
+	resp.Diagnostics.Append(setConfiguredPropertyKeys(ctx, resp.Private, model.Properties)...)
+	resp.Diagnostics.Append(resp.State.Set(ctx, model)...)
+}
+
+func (r *msGraphObjectResource) post(ctx context.Context, model msGraphObjectResourceModel, collection string) (string, diag.Diagnostics) {
+	http, diags := r.newPostRequest(ctx, model, collection)
+	if diags.HasError() {
+		return "", diags
+	}
+
+	response, err := post(http, collection)
+	if diags := ensurePropertiesResponseSucceeded(response, err); diags.HasError() {
+		return "", diags
+	}
+
+	return ensureResponseHasObjectID(response)
+}
+
+func (r *msGraphObjectResource) newPostRequest(ctx context.Context, model msGraphObjectResourceModel, collection string) (*resty.Request, diag.Diagnostics) {
+	if model.RetryCreateOnServerError.ValueBool() {
+		// Retrying is only safe as long as no matching object has been created.
+		ctx = client.WithRetryProbe(ctx, func(ctx context.Context) bool {
+			return isConsistentlyAbsent(ctx, func(ctx context.Context) (bool, diag.Diagnostics) {
+				http := r.client.R(ctx, model.ApiVersion)
+				odataQuery{Filter: model.MatchFilter.ValueString()}.apply(http)
+
+				objectIDs, diags := ensureListObjectIDs(http, collection, 1)
+				return len(objectIDs) > 0, diags
+			})
+		})
+	}
+
+	body, diags := ensureMergeProperties(model.Properties, model.CreateOnlyProperties)
+	if diags.HasError() {
+		return nil, diags
+	}
+
 	http := r.client.R(ctx, model.ApiVersion)
+	setRequestJSONBody(http, body)
 
-	resp.Diagnostics.Append(ensureRequestSetBodyFromDynamic(http, model.Properties)...)
-	if resp.Diagnostics.HasError() {
-		resp.Diagnostics.Append(diags...)
-		return
+	return http, noErrors()
+}
+
+// upsert creates or updates the object identified by the alternate key.
+// Microsoft Graph only returns the object when it was created.
+func (r *msGraphObjectResource) upsert(ctx context.Context, model msGraphObjectResourceModel, collection string) (string, diag.Diagnostics) {
+	url := fmt.Sprintf("%s(%s)", collection, model.AlternateKey.ValueString())
+
+	// Create-only properties are rejected with upsert, as it may update an
+	// existing object.
+	body, diags := ensureMergeProperties(model.Properties, types.DynamicNull())
+	if diags.HasError() {
+		return "", diags
 	}
 
-	response, err := post(http, path)
-	resp.Diagnostics.Append(ensureHttpResponseSucceeded(response, err)...)
-	if resp.Diagnostics.HasError() {
-		resp.Diagnostics.Append(diags...)
-		return
+	http := r.client.R(ctx, model.ApiVersion).SetHeader("Prefer", "create-if-missing")
+	setRequestJSONBody(http, body)
+
+	response, err := patch(http, url)
+	if diags := ensurePropertiesResponseSucceeded(response, err); diags.HasError() {
+		return "", diags
 	}
 
-	objectID, diags := ensureResponseHasObjectID(response)
+	if len(response.Body()) == 0 {
+		response, err = get(r.client.R(ctx, model.ApiVersion).SetQueryParam("$select", "id"), url)
+		if diags := ensureHttpResponseSucceeded(response, err); diags.HasError() {
+			return "", diags
+		}
+	}
+
+	return ensureResponseHasObjectID(response)
+}
+
+// adopt creates the object, or takes ownership of the existing object matching
+// the match filter when the collection reports a conflict and applies the
+// properties to it.
+func (r *msGraphObjectResource) adopt(ctx context.Context, model msGraphObjectResourceModel, collection string) (string, diag.Diagnostics) {
+	request, diags := r.newPostRequest(ctx, model, collection)
 	if diags.HasError() {
-		resp.Diagnostics.Append(diags...)
-		return
+		return "", diags
 	}
 
-	id := id.New(path, objectID)
-	model.ID = id.AsString()
+	response, err := post(request, collection)
+	if err != nil || response.StatusCode() != http.StatusConflict {
+		if diags := ensurePropertiesResponseSucceeded(response, err); diags.HasError() {
+			return "", diags
+		}
+		return ensureResponseHasObjectID(response)
+	}
 
-	content, diags := ensureGetObjectAsDynamic(http, id.Path)
+	id, diags := ensureFindObjectID(r.client.R(ctx, model.ApiVersion), types.StringValue(collection), model.MatchFilter)
 	if diags.HasError() {
-		resp.Diagnostics.Append(diags...)
+		return "", diags
+	}
+
+	// The existing object may differ from the configuration, so the properties
+	// that differ are applied to it. Nothing is sent when they are equal, as
+	// some collections such as directoryRoles do not support updates.
+	existing, diags := ensureGetObjectAsDynamic(r.client.R(ctx, model.ApiVersion), id.Path)
+	if diags.HasError() {
+		return "", diags
+	}
+
+	properties, diags := ensureDecodeProperties(model.Properties)
+	if diags.HasError() {
+		return "", diags
+	}
+
+	body, changed, err := dynamic.Diff(existing, properties, dynamic.DiffOptions{UnorderedPrimitives: true})
+	if err != nil {
+		return "", dynamicErrorDiagnostics("Failed to compute changed properties.", err)
+	}
+
+	if changed {
+		http := r.client.R(ctx, model.ApiVersion)
+		setRequestJSONBody(http, body)
+
+		response, err := patch(http, id.Path)
+		if diags := ensurePropertiesResponseSucceeded(response, err); diags.HasError() {
+			return "", diags
+		}
+	}
+
+	return id.ObjectId(), noErrors()
+}
+
+// restoreDeleted restores the soft-deleted object matching the match filter
+// and applies the properties to it.
+func (r *msGraphObjectResource) restoreDeleted(ctx context.Context, model msGraphObjectResourceModel, collection string) (string, bool, diag.Diagnostics) {
+	itemType := model.DeletedItemType.ValueString()
+	if itemType == "" {
+		// ValidateConfig requires deleted_item_type for unknown collections.
+		itemType, _ = deletedItemType(collection)
+	}
+
+	objectID, found, diags := ensureRestoreDeletedItem(ctx, r.client, model.ApiVersion, itemType, model.MatchFilter.ValueString())
+	if diags.HasError() || !found {
+		return "", false, diags
+	}
+
+	http := r.client.R(ctx, model.ApiVersion)
+
+	properties, diags := ensureDecodeProperties(model.Properties)
+	if diags.HasError() {
+		return "", false, diags
+	}
+
+	if diags := ensureRequestSetBodyFromDynamic(http, properties); diags.HasError() {
+		return "", false, diags
+	}
+
+	response, err := patch(http, id.New(collection, objectID).Path)
+	if diags := ensurePropertiesResponseSucceeded(response, err); diags.HasError() {
+		return "", false, diags
+	}
+
+	return objectID, true, noErrors()
+}
+
+func (r *msGraphObjectResource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var model msGraphObjectResourceModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &model)...)
+	if resp.Diagnostics.HasError() {
 		return
 	}
-	model.Output = content
 
-	resp.Diagnostics.Append(resp.State.Set(ctx, model)...)
+	_, diags := model.ensureOptions()
+	resp.Diagnostics.Append(diags...)
+
+	for attribute, value := range map[string]types.Dynamic{
+		"properties":             model.Properties,
+		"create_only_properties": model.CreateOnlyProperties,
+	} {
+		if _, err := dynamic.Decode(value); err != nil {
+			resp.Diagnostics.AddAttributeError(path.Root(attribute), "Invalid JSON object.", err.Error())
+		}
+	}
+
+	if model.RestoreIfDeleted.ValueBool() && model.MatchFilter.IsNull() {
+		resp.Diagnostics.AddAttributeError(path.Root("match_filter"), "Missing match filter.", "The match_filter attribute is required to find the object to restore when restore_if_deleted is true.")
+	}
+
+	if model.RetryCreateOnServerError.ValueBool() && model.MatchFilter.IsNull() {
+		resp.Diagnostics.AddAttributeError(path.Root("match_filter"), "Missing match filter.", "The match_filter attribute is required to check that no object was created before retrying when retry_create_on_server_error is true.")
+	}
+
+	if model.RestoreIfDeleted.ValueBool() && model.DeletedItemType.IsNull() && !model.Collection.IsUnknown() {
+		if _, ok := deletedItemType(model.Collection.ValueString()); !ok {
+			resp.Diagnostics.AddAttributeError(path.Root("deleted_item_type"), "Missing deleted item type.", fmt.Sprintf("The deleted_item_type attribute is required when restore_if_deleted is true, as the type of the objects of %q in directory/deletedItems is not known.", model.Collection.ValueString()))
+		}
+	}
+
+	switch model.CreateMode.ValueString() {
+	case createModeUpsert:
+		if model.AlternateKey.IsNull() {
+			resp.Diagnostics.AddAttributeError(path.Root("alternate_key"), "Missing alternate key.", "The alternate_key attribute is required when create_mode is upsert.")
+		}
+		if !model.CreateOnlyProperties.IsNull() {
+			resp.Diagnostics.AddAttributeError(path.Root("create_only_properties"), "Unsupported create-only properties.", "The create_only_properties attribute cannot be used when create_mode is upsert, as the upsert also updates an existing object.")
+		}
+	case createModeAdopt:
+		if model.MatchFilter.IsNull() {
+			resp.Diagnostics.AddAttributeError(path.Root("match_filter"), "Missing match filter.", "The match_filter attribute is required to find the object to adopt when create_mode is adopt.")
+		}
+	}
 }
 
 func (r *msGraphObjectResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
@@ -150,20 +525,38 @@ func (r *msGraphObjectResource) Read(ctx context.Context, req resource.ReadReque
 
 	http := r.client.R(ctx, model.ApiVersion)
 
-	content, diags := ensureGetObjectAsDynamic(http, id.Path)
+	content, found, diags := ensureFindObjectAsDynamic(http, id.Path)
 	if diags.HasError() {
 		resp.Diagnostics.Append(diags...)
 		return
 	}
+
+	if !found {
+		resp.Diagnostics.AddWarning(
+			fmt.Sprintf("Object %q not found, removing it from state.", id.Path),
+			notFoundDetail(ctx, r.client, model.ApiVersion, id),
+		)
+		resp.State.RemoveResource(ctx)
+		return
+	}
 	model.Output = content
 
-	properties, err := dynamic.UpdateWithSchemaPreservation(content, model.Properties)
+	model.defaultNullAttributes()
+
+	options, diags := model.ensureOptions()
+	if diags.HasError() {
+		resp.Diagnostics.Append(diags...)
+		return
+	}
+
+	properties, err := dynamic.UpdateWithSchemaPreservation(content, model.Properties, options.updateOptions())
 	if err != nil {
 		resp.Diagnostics.Append(errorDiagnostics("Failed to apply dynamic properties.", err.Error())...)
 		return
 	}
 	model.Properties = properties
 
+	resp.Diagnostics.Append(ensureConfiguredPropertyKeysRecorded(ctx, req.Private, resp.Private, model.Properties)...)
 	resp.Diagnostics.Append(resp.State.Set(ctx, model)...)
 }
 
@@ -181,27 +574,64 @@ func (r *msGraphObjectResource) Update(ctx context.Context, req resource.UpdateR
 		return
 	}
 
-	http := r.client.R(ctx, model.ApiVersion)
-
-	resp.Diagnostics.Append(ensureRequestSetBodyFromDynamic(http, model.Properties)...)
+	var state msGraphObjectResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
 	if resp.Diagnostics.HasError() {
-		resp.Diagnostics.Append(diags...)
 		return
 	}
 
-	response, err := patch(http, id.Path)
-	if diags := ensureHttpResponseSucceeded(response, err); diags.HasError() {
-		resp.Diagnostics.Append(diags...)
-		return
+	var removed []string
+	if model.ClearRemovedProperties.ValueBool() {
+		removed, diags = ensureConfiguredPropertyKeys(ctx, req.Private)
+		if diags.HasError() {
+			resp.Diagnostics.Append(diags...)
+			return
+		}
 	}
 
-	content, diags := ensureGetObjectAsDynamic(http, id.Path)
+	options, diags := model.ensureOptions()
 	if diags.HasError() {
 		resp.Diagnostics.Append(diags...)
 		return
 	}
-	model.Output = content
 
+	// Changes of ignored properties are not sent to Microsoft Graph.
+	properties, err := dynamic.IgnoreChanges(model.Properties, state.Properties, options.ignoreChanges)
+	if err != nil {
+		resp.Diagnostics.Append(dynamicErrorDiagnostics("Failed to ignore changes.", err)...)
+		return
+	}
+
+	body, changed, err := dynamic.Diff(state.Properties, properties, options.diffOptions(removed))
+	if err != nil {
+		resp.Diagnostics.Append(dynamicErrorDiagnostics("Failed to compute changed properties.", err)...)
+		return
+	}
+
+	http := r.client.R(ctx, model.ApiVersion)
+
+	if changed {
+		setRequestJSONBody(http, body)
+
+		response, err := patch(http, id.Path)
+		if diags := ensurePropertiesResponseSucceeded(response, err); diags.HasError() {
+			resp.Diagnostics.Append(diags...)
+			return
+		}
+	}
+
+	// The planned output is kept when it is known, as nothing that affects it
+	// changed.
+	if model.Output.IsUnknown() {
+		content, diags := ensureGetObjectAsDynamic(r.client.R(ctx, model.ApiVersion), id.Path)
+		if diags.HasError() {
+			resp.Diagnostics.Append(diags...)
+			return
+		}
+		model.Output = content
+	}
+
+	resp.Diagnostics.Append(setConfiguredPropertyKeys(ctx, resp.Private, model.Properties)...)
 	resp.Diagnostics.Append(resp.State.Set(ctx, model)...)
 }
 
@@ -219,17 +649,29 @@ func (r *msGraphObjectResource) Delete(ctx context.Context, req resource.DeleteR
 		return
 	}
 
+	if model.DestroyBehavior.ValueString() == destroyBehaviorAbandon {
+		resp.Diagnostics.AddWarning(
+			fmt.Sprintf("Object %q abandoned.", id.Path),
+			"The object was removed from the state but was not deleted from Microsoft Graph.",
+		)
+		return
+	}
+
 	http := r.client.R(ctx, model.ApiVersion)
 
 	response, err := delete(http, id.Path)
-	if response.StatusCode() == httpStatusNotFound {
-		return
+	deleted := !isNotFound(response, err)
+	if deleted {
+		resp.Diagnostics.Append(ensureHttpResponseSucceeded(response, err)...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
 	}
 
-	resp.Diagnostics.Append(ensureHttpResponseSucceeded(response, err)...)
-	if resp.Diagnostics.HasError() {
-		resp.Diagnostics.Append(diags...)
-		return
+	if model.DestroyBehavior.ValueString() == destroyBehaviorPermanentDelete {
+		// An object that was already gone may have been purged before, so it
+		// is only waited for when it was just deleted.
+		resp.Diagnostics.Append(ensurePurgeDeletedItem(ctx, r.client, model.ApiVersion, id.ObjectId(), deleted)...)
 	}
 }
 
@@ -245,6 +687,7 @@ func (r *msGraphObjectResource) ImportState(ctx context.Context, req resource.Im
 		Collection: types.StringValue(id.Collection()),
 		ApiVersion: types.StringNull(),
 	}
+	model.defaultNullAttributes()
 
 	if apiVersion := id.ApiVersion(); apiVersion != "" {
 		model.ApiVersion = types.StringValue(apiVersion)
@@ -252,14 +695,243 @@ func (r *msGraphObjectResource) ImportState(ctx context.Context, req resource.Im
 
 	http := r.client.R(ctx, model.ApiVersion)
 
-	content, diags := ensureGetObjectAsDynamic(http, id.Path)
+	content, found, diags := ensureFindObjectAsDynamic(http, id.Path)
 	if diags.HasError() {
 		resp.Diagnostics.Append(diags...)
 		return
 	}
 
+	if !found {
+		resp.Diagnostics.AddError(
+			fmt.Sprintf("Cannot import non-existent object %q.", id.Path),
+			notFoundDetail(ctx, r.client, model.ApiVersion, id),
+		)
+		return
+	}
+
 	model.Output = content
 	model.Properties = content
 
+	// The configured properties are not known until the object is updated, and
+	// the imported properties must not be cleared.
+	resp.Diagnostics.Append(resp.Private.SetKey(ctx, privateStateConfiguredProperties, []byte("[]"))...)
 	resp.Diagnostics.Append(resp.State.Set(ctx, model)...)
 }
+
+// defaultNullAttributes sets the attributes that have a default but are not in
+// the state, e.g. after import, to their default.
+func (model *msGraphObjectResourceModel) defaultNullAttributes() {
+	if model.DestroyBehavior.IsNull() {
+		model.DestroyBehavior = types.StringValue(destroyBehaviorDelete)
+	}
+	if model.RestoreIfDeleted.IsNull() {
+		model.RestoreIfDeleted = types.BoolValue(false)
+	}
+	if model.RetryCreateOnServerError.IsNull() {
+		model.RetryCreateOnServerError = types.BoolValue(false)
+	}
+	if model.CreateMode.IsNull() {
+		model.CreateMode = types.StringValue(createModePost)
+	}
+	if model.ClearRemovedProperties.IsNull() {
+		model.ClearRemovedProperties = types.BoolValue(true)
+	}
+	if model.ReplaceOnCreateOnlyChanges.IsNull() {
+		model.ReplaceOnCreateOnlyChanges = types.BoolValue(false)
+	}
+}
+
+type privateStateSetter interface {
+	SetKey(ctx context.Context, key string, value []byte) diag.Diagnostics
+}
+
+func setConfiguredPropertyKeys(ctx context.Context, private privateStateSetter, properties types.Dynamic) diag.Diagnostics {
+	propertiesMap, diags := ensureDynamicAsMap(properties)
+	if diags.HasError() {
+		return diags
+	}
+
+	keys := make([]string, 0, len(propertiesMap))
+	for key := range propertiesMap {
+		keys = append(keys, key)
+	}
+	slices.Sort(keys)
+
+	value, err := json.Marshal(keys)
+	if err != nil {
+		return errorDiagnostics("Failed to marshal configured properties to JSON.", err.Error())
+	}
+
+	return private.SetKey(ctx, privateStateConfiguredProperties, value)
+}
+
+// ensureConfiguredPropertyKeysRecorded records the configured properties of
+// objects created before they were kept in the private state, from the keys of
+// their properties in the state. Objects that were imported and never updated
+// have every property of the object in the state, including its `id`, which
+// was not configured, so nothing is recorded for them.
+func ensureConfiguredPropertyKeysRecorded(ctx context.Context, current privateState, updated privateStateSetter, properties types.Dynamic) diag.Diagnostics {
+	value, diags := current.GetKey(ctx, privateStateConfiguredProperties)
+	if diags.HasError() || len(value) > 0 {
+		return diags
+	}
+
+	propertiesMap, diags := ensureDynamicAsMap(properties)
+	if diags.HasError() {
+		return diags
+	}
+
+	if _, ok := propertiesMap["id"]; ok {
+		return updated.SetKey(ctx, privateStateConfiguredProperties, []byte("[]"))
+	}
+
+	return setConfiguredPropertyKeys(ctx, updated, properties)
+}
+
+func ensureConfiguredPropertyKeys(ctx context.Context, private privateState) ([]string, diag.Diagnostics) {
+	value, diags := private.GetKey(ctx, privateStateConfiguredProperties)
+	if diags.HasError() || len(value) == 0 {
+		return nil, diags
+	}
+
+	var keys []string
+	if err := json.Unmarshal(value, &keys); err != nil {
+		return nil, errorDiagnostics("Failed to parse configured properties.", err.Error())
+	}
+
+	return keys, noErrors()
+}
+
+// requiresReplaceIfAttributeIsTrue requires replacement when the planned value
+// of the boolean `attribute` is true.
+func requiresReplaceIfAttributeIsTrue(attribute string) dynamicplanmodifier.RequiresReplaceIfFunc {
+	return func(ctx context.Context, req planmodifier.DynamicRequest, resp *dynamicplanmodifier.RequiresReplaceIfFuncResponse) {
+		var replace types.Bool
+		resp.Diagnostics.Append(req.Plan.GetAttribute(ctx, path.Root(attribute), &replace)...)
+		resp.RequiresReplace = replace.ValueBool()
+	}
+}
+
+func objectPlanOptions(ctx context.Context, req planmodifier.DynamicRequest) (dynamic.PlanOptions, diag.Diagnostics) {
+	var model msGraphObjectResourceModel
+	diags := req.Config.Get(ctx, &model)
+	if diags.HasError() {
+		return dynamic.PlanOptions{}, diags
+	}
+
+	options, diags := model.ensureOptions()
+	if diags.HasError() {
+		return dynamic.PlanOptions{}, diags
+	}
+
+	return options.planOptions(), noErrors()
+}
+
+// objectOptions are the parsed attributes that control how the properties are
+// compared, planned, refreshed and updated.
+type objectOptions struct {
+	writeOnly       []dynamic.Path
+	ignoreChanges   []dynamic.Path
+	replaceTriggers []dynamic.Path
+	unordered       []dynamic.Path
+	normalizers     []dynamic.PathNormalizer
+	arrayKeys       []dynamic.ArrayKey
+}
+
+// ensureOptions parses the option attributes of the model, reporting every
+// invalid attribute.
+func (model msGraphObjectResourceModel) ensureOptions() (objectOptions, diag.Diagnostics) {
+	var options objectOptions
+	var diags, attributeDiags diag.Diagnostics
+
+	options.writeOnly, attributeDiags = ensureListAsPaths(model.WriteOnlyProperties, "write_only_properties")
+	diags.Append(attributeDiags...)
+
+	options.ignoreChanges, attributeDiags = ensureListAsPaths(model.IgnoreChangesPaths, "ignore_changes_paths")
+	diags.Append(attributeDiags...)
+
+	options.replaceTriggers, attributeDiags = ensureListAsPaths(model.ReplaceTriggersPaths, "replace_triggers_paths")
+	diags.Append(attributeDiags...)
+
+	options.unordered, attributeDiags = ensureListAsPaths(model.UnorderedArrayPaths, "unordered_array_paths")
+	diags.Append(attributeDiags...)
+
+	options.normalizers, attributeDiags = ensureMapAsNormalizers(model.PropertyNormalizers, "property_normalizers")
+	diags.Append(attributeDiags...)
+
+	options.arrayKeys, attributeDiags = ensureMapAsArrayKeys(model.ArrayKeys, "array_keys")
+	diags.Append(attributeDiags...)
+
+	return options, diags
+}
+
+func (options objectOptions) planOptions() dynamic.PlanOptions {
+	return dynamic.PlanOptions{
+		IgnoreChanges:       options.ignoreChanges,
+		ReplaceTriggers:     options.replaceTriggers,
+		Unordered:           options.unordered,
+		UnorderedPrimitives: true,
+		ArrayKeys:           options.arrayKeys,
+		Normalizers:         options.normalizers,
+	}
+}
+
+func (options objectOptions) updateOptions() dynamic.UpdateOptions {
+	return dynamic.UpdateOptions{
+		WriteOnly:           options.writeOnly,
+		Unordered:           options.unordered,
+		UnorderedPrimitives: true,
+		ArrayKeys:           options.arrayKeys,
+		Normalizers:         options.normalizers,
+	}
+}
+
+// diffOptions returns the options to compute the changes to send, setting the
+// `removed` properties to null.
+func (options objectOptions) diffOptions(removed []string) dynamic.DiffOptions {
+	return dynamic.DiffOptions{
+		Removed:             removed,
+		Unordered:           options.unordered,
+		UnorderedPrimitives: true,
+		ArrayKeys:           options.arrayKeys,
+		Normalizers:         options.normalizers,
+	}
+}
+
+// outputPlanModifier keeps the output of the state when the planned properties
+// and API version are unchanged, so that the output is only unknown when the
+// object actually changes.
+type outputPlanModifier struct{}
+
+func (m outputPlanModifier) Description(ctx context.Context) string {
+	return "Use the state value when the properties and API version are unchanged."
+}
+
+func (m outputPlanModifier) MarkdownDescription(ctx context.Context) string {
+	return m.Description(ctx)
+}
+
+func (m outputPlanModifier) PlanModifyDynamic(ctx context.Context, req planmodifier.DynamicRequest, resp *planmodifier.DynamicResponse) {
+	if req.StateValue.IsNull() || !req.PlanValue.IsUnknown() {
+		return
+	}
+
+	var planProperties, stateProperties types.Dynamic
+	var planApiVersion, stateApiVersion types.String
+
+	resp.Diagnostics.Append(req.Plan.GetAttribute(ctx, path.Root("properties"), &planProperties)...)
+	resp.Diagnostics.Append(req.State.GetAttribute(ctx, path.Root("properties"), &stateProperties)...)
+	resp.Diagnostics.Append(req.Plan.GetAttribute(ctx, path.Root("api_version"), &planApiVersion)...)
+	resp.Diagnostics.Append(req.State.GetAttribute(ctx, path.Root("api_version"), &stateApiVersion)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if !dynamic.IsFullyKnown(planProperties) || planApiVersion.IsUnknown() {
+		return
+	}
+
+	if planProperties.Equal(stateProperties) && planApiVersion.Equal(stateApiVersion) {
+		resp.PlanValue = req.StateValue
+	}
+}
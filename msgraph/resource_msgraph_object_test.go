@@ -2,11 +2,13 @@ package msgraph
 
 import (
 	"fmt"
+	"regexp"
 	"testing"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/acctest"
 	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
 	"github.com/hashicorp/terraform-plugin-testing/plancheck"
+	"github.com/hashicorp/terraform-plugin-testing/terraform"
 )
 
 func TestAccMsGraphObjectResource(t *testing.T) {
@@ -73,3 +75,476 @@ func msGraphGroupResourceConfig(displayName string, mailNickname string) string
 	}
 	`, displayName, mailNickname)
 }
+
+func TestAccMsGraphObjectResource_invalidProperty(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: protoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: defaultProviderConfigWith(`
+				resource "msgraph_object" "group" {
+					collection = "groups"
+					properties = {
+						displayName = "%s"
+						mailEnabled = false
+						mailNickname = "%s"
+						securityEnabled = true
+						notAProperty = true
+					}
+				}
+				`, acctest.RandString(10), acctest.RandString(10)),
+				ExpectError: regexp.MustCompile(`(?s)Request_BadRequest:.*Request ID:`),
+			},
+		},
+	})
+}
+
+func TestAccMsGraphObjectResource_deletedOutsideTerraform(t *testing.T) {
+	const resourceName = "msgraph_object.group"
+	groupName := acctest.RandString(10)
+	var groupPath string
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: protoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: msGraphGroupResourceConfig(groupName, groupName),
+				Check:  testAccExtractResourceAttr(resourceName, "id", &groupPath),
+			},
+			{
+				PreConfig: func() { testAccDeleteObject(t, groupPath) },
+				Config:    msGraphGroupResourceConfig(groupName, groupName),
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectResourceAction(resourceName, plancheck.ResourceActionCreate),
+					},
+				},
+			},
+		},
+	})
+}
+
+func TestAccMsGraphObjectResource_permanentDelete(t *testing.T) {
+	const resourceName = "msgraph_object.group"
+	groupName := acctest.RandString(10)
+	var groupPath string
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: protoV6ProviderFactories,
+		CheckDestroy:             testAccCheckNotSoftDeleted(&groupPath),
+		Steps: []resource.TestStep{
+			{
+				Config: defaultProviderConfigWith(`
+				resource "msgraph_object" "group" {
+					collection = "groups"
+					properties = {
+						displayName = "%s"
+						mailEnabled = false
+						mailNickname = "%s"
+						securityEnabled = true
+					}
+					destroy_behavior = "permanent_delete"
+				}
+				`, groupName, groupName),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					testAccExtractResourceAttr(resourceName, "id", &groupPath),
+					resource.TestCheckResourceAttr(resourceName, "destroy_behavior", "permanent_delete"),
+					resource.TestCheckResourceAttr(resourceName, "output.displayName", groupName),
+				),
+			},
+		},
+	})
+}
+
+func TestAccMsGraphObjectResource_restoreIfDeleted(t *testing.T) {
+	const resourceName = "msgraph_object.group"
+	groupName := acctest.RandString(10)
+	config := defaultProviderConfigWith(`
+	resource "msgraph_object" "group" {
+		collection = "groups"
+		properties = {
+			displayName = "%s"
+			mailEnabled = false
+			mailNickname = "%s"
+			securityEnabled = true
+		}
+		restore_if_deleted = true
+		match_filter       = "mailNickname eq '%s'"
+	}
+	`, groupName, groupName, groupName)
+	var groupPath string
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: protoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: config,
+				Check:  testAccExtractResourceAttr(resourceName, "id", &groupPath),
+			},
+			{
+				Config:  config,
+				Destroy: true,
+			},
+			{
+				Config: config,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					func(s *terraform.State) error {
+						return resource.TestCheckResourceAttr(resourceName, "id", groupPath)(s)
+					},
+					resource.TestCheckResourceAttr(resourceName, "output.displayName", groupName),
+				),
+			},
+		},
+	})
+}
+
+func TestAccMsGraphObjectResource_restoreIfDeletedRequiresDeletedItemType(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: protoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: defaultProviderConfigWith(`
+				resource "msgraph_object" "policy" {
+					collection = "identity/conditionalAccess/policies"
+					properties = {
+						displayName = "policy"
+					}
+					restore_if_deleted = true
+					match_filter       = "displayName eq 'policy'"
+				}
+				`),
+				ExpectError: regexp.MustCompile(`Missing deleted item type`),
+			},
+		},
+	})
+}
+
+func TestAccMsGraphObjectResource_upsert(t *testing.T) {
+	const resourceName = "msgraph_object.application"
+	uniqueName := acctest.RandString(10)
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: protoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: defaultProviderConfigWith(`
+				resource "msgraph_object" "application" {
+					collection = "applications"
+					properties = {
+						displayName = "%s"
+					}
+					create_mode   = "upsert"
+					alternate_key = "uniqueName='%s'"
+				}
+				`, uniqueName, uniqueName),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttrSet(resourceName, "id"),
+					resource.TestCheckResourceAttr(resourceName, "output.uniqueName", uniqueName),
+					resource.TestCheckResourceAttr(resourceName, "output.displayName", uniqueName),
+				),
+			},
+		},
+	})
+}
+
+func TestAccMsGraphObjectResource_upsertExisting(t *testing.T) {
+	const resourceName = "msgraph_object.upserted"
+	uniqueName := acctest.RandString(10)
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: protoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: defaultProviderConfigWith(`
+				resource "msgraph_object" "existing" {
+					collection = "applications"
+					properties = {
+						displayName = "%s"
+						uniqueName  = "%s"
+					}
+				}
+
+				resource "msgraph_object" "upserted" {
+					collection = "applications"
+					properties = {
+						description = "upserted"
+					}
+					create_mode      = "upsert"
+					alternate_key    = "uniqueName='%s'"
+					destroy_behavior = "abandon"
+					depends_on       = [msgraph_object.existing]
+				}
+				`, uniqueName, uniqueName, uniqueName),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttrPair(resourceName, "id", "msgraph_object.existing", "id"),
+					resource.TestCheckResourceAttr(resourceName, "output.displayName", uniqueName),
+					resource.TestCheckResourceAttr(resourceName, "output.description", "upserted"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccMsGraphObjectResource_upsertRejectsCreateOnlyProperties(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: protoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: defaultProviderConfigWith(`
+				resource "msgraph_object" "application" {
+					collection = "applications"
+					properties = {
+						displayName = "%s"
+					}
+					create_only_properties = {
+						description = "created"
+					}
+					create_mode   = "upsert"
+					alternate_key = "uniqueName='%s'"
+				}
+				`, acctest.RandString(10), acctest.RandString(10)),
+				ExpectError: regexp.MustCompile(`Unsupported create-only properties`),
+			},
+		},
+	})
+}
+
+func TestAccMsGraphObjectResource_adopt(t *testing.T) {
+	const resourceName = "msgraph_object.role"
+	// The Global Administrator role is always activated, so activating it
+	// again reports a conflict.
+	const roleTemplateID = "62e90394-69f5-4237-9190-012177145e10"
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: protoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: defaultProviderConfigWith(`
+				resource "msgraph_object" "role" {
+					collection = "directoryRoles"
+					properties = {
+						roleTemplateId = "%s"
+					}
+					create_mode      = "adopt"
+					match_filter     = "roleTemplateId eq '%s'"
+					destroy_behavior = "abandon"
+				}
+				`, roleTemplateID, roleTemplateID),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttrSet(resourceName, "id"),
+					resource.TestCheckResourceAttr(resourceName, "output.roleTemplateId", roleTemplateID),
+					resource.TestCheckResourceAttr(resourceName, "output.displayName", "Global Administrator"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccMsGraphObjectResource_createModeRequiresMatchFilter(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: protoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: defaultProviderConfigWith(`
+				resource "msgraph_object" "role" {
+					collection = "directoryRoles"
+					properties = {
+						roleTemplateId = "fe930be7-5e62-47db-91af-98c3a49a38b1"
+					}
+					create_mode = "adopt"
+				}
+				`),
+				ExpectError: regexp.MustCompile(`Missing match filter`),
+			},
+		},
+	})
+}
+
+func TestAccMsGraphObjectResource_clearRemovedProperties(t *testing.T) {
+	const resourceName = "msgraph_object.group"
+	groupName := acctest.RandString(10)
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: protoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: defaultProviderConfigWith(`
+				resource "msgraph_object" "group" {
+					collection = "groups"
+					properties = {
+						displayName = "%s"
+						description = "%s"
+						mailEnabled = false
+						mailNickname = "%s"
+						securityEnabled = true
+					}
+				}
+				`, groupName, groupName, groupName),
+				Check: resource.TestCheckResourceAttr(resourceName, "output.description", groupName),
+			},
+			{
+				Config: msGraphGroupResourceConfig(groupName, groupName),
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectResourceAction(resourceName, plancheck.ResourceActionUpdate),
+					},
+				},
+				Check: resource.TestCheckNoResourceAttr(resourceName, "output.description"),
+			},
+		},
+	})
+}
+
+func TestAccMsGraphObjectResource_clearRemovedPropertiesAfterImport(t *testing.T) {
+	const resourceName = "msgraph_object.group"
+	groupName := acctest.RandString(10)
+	updatedGroupName := fmt.Sprintf("%s-updated", groupName)
+
+	config := func(displayName string) string {
+		return defaultProviderConfigWith(`
+		resource "msgraph_object" "group" {
+			collection = "groups"
+			properties = {
+				displayName = "%s"
+				description = "%s"
+				mailEnabled = false
+				mailNickname = "%s"
+				securityEnabled = true
+			}
+		}
+		`, displayName, groupName, groupName)
+	}
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: protoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: config(groupName),
+				Check:  resource.TestCheckResourceAttr(resourceName, "output.description", groupName),
+			},
+			{
+				ResourceName:       resourceName,
+				ImportState:        true,
+				ImportStatePersist: true,
+			},
+			{
+				Config: config(updatedGroupName),
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectResourceAction(resourceName, plancheck.ResourceActionUpdate),
+					},
+				},
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr(resourceName, "output.displayName", updatedGroupName),
+					resource.TestCheckResourceAttr(resourceName, "output.description", groupName),
+				),
+			},
+			{
+				Config: msGraphGroupResourceConfig(updatedGroupName, groupName),
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectResourceAction(resourceName, plancheck.ResourceActionUpdate),
+					},
+				},
+				Check: resource.TestCheckNoResourceAttr(resourceName, "output.description"),
+			},
+		},
+	})
+}
+
+func TestAccMsGraphObjectResource_createOnlyProperties(t *testing.T) {
+	const resourceName = "msgraph_object.group"
+	groupName := acctest.RandString(10)
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: protoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: msGraphGroupCreateOnlyResourceConfig(groupName, "created", false),
+				Check:  resource.TestCheckResourceAttr(resourceName, "output.description", "created"),
+			},
+			{
+				Config: msGraphGroupCreateOnlyResourceConfig(groupName, "changed", false),
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectResourceAction(resourceName, plancheck.ResourceActionUpdate),
+					},
+				},
+				Check: resource.TestCheckResourceAttr(resourceName, "output.description", "created"),
+			},
+			{
+				Config: msGraphGroupCreateOnlyResourceConfig(groupName, "replaced", true),
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectResourceAction(resourceName, plancheck.ResourceActionDestroyBeforeCreate),
+					},
+				},
+				Check: resource.TestCheckResourceAttr(resourceName, "output.description", "replaced"),
+			},
+		},
+	})
+}
+
+func msGraphGroupCreateOnlyResourceConfig(groupName string, description string, replace bool) string {
+	return defaultProviderConfigWith(`
+	resource "msgraph_object" "group" {
+		collection = "groups"
+		properties = {
+			displayName = "%s"
+			mailEnabled = false
+			mailNickname = "%s"
+			securityEnabled = true
+		}
+		create_only_properties = {
+			description = "%s"
+		}
+		replace_on_create_only_changes = %t
+	}
+	`, groupName, groupName, description, replace)
+}
+
+func TestAccMsGraphObjectResource_jsonStringProperties(t *testing.T) {
+	const resourceName = "msgraph_object.group"
+	groupName := acctest.RandString(10)
+
+	config := defaultProviderConfigWith(`
+	resource "msgraph_object" "group" {
+		collection = "groups"
+		properties = jsonencode({
+			displayName = "%s"
+			mailEnabled = false
+			mailNickname = "%s"
+			securityEnabled = true
+		})
+	}
+	`, groupName, groupName)
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: protoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: config,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr(resourceName, "output.displayName", groupName),
+					resource.TestCheckResourceAttr(resourceName, "output.mailNickname", groupName),
+				),
+			},
+			{
+				Config: config,
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectResourceAction(resourceName, plancheck.ResourceActionNoop),
+					},
+				},
+			},
+			{
+				Config: msGraphGroupResourceConfig(groupName, groupName),
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectResourceAction(resourceName, plancheck.ResourceActionNoop),
+					},
+				},
+			},
+		},
+	})
+}
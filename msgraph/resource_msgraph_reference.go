@@ -0,0 +1,193 @@
+package msgraph
+
+import (
+	"context"
+
+	"github.com/GoodCloudWorks/terraform-provider-msgraph/msgraph/client"
+	"github.com/GoodCloudWorks/terraform-provider-msgraph/msgraph/id"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringdefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+var (
+	_ resource.Resource                = &msGraphReferenceResource{}
+	_ resource.ResourceWithConfigure   = &msGraphReferenceResource{}
+	_ resource.ResourceWithImportState = &msGraphReferenceResource{}
+)
+
+type msGraphReferenceResource struct {
+	client client.MsGraphClient
+}
+
+type msGraphReferenceResourceModel struct {
+	ID               types.String `tfsdk:"id"`
+	Object           types.String `tfsdk:"object"`
+	Navigation       types.String `tfsdk:"navigation"`
+	TargetID         types.String `tfsdk:"target_id"`
+	TargetCollection types.String `tfsdk:"target_collection"`
+	ApiVersion       types.String `tfsdk:"api_version"`
+}
+
+func NewMsGraphReferenceResource() resource.Resource {
+	return &msGraphReferenceResource{}
+}
+
+func (r *msGraphReferenceResource) Configure(_ context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	if v, ok := req.ProviderData.(client.MsGraphClient); ok {
+		r.client = v
+	}
+}
+
+func (*msGraphReferenceResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_reference"
+}
+
+func (*msGraphReferenceResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "This resource provides the ability to manage a single `$ref` link of a Microsoft Graph navigation property, such as a group member or owner.",
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Computed:    true,
+				Description: "The ID of the reference, in the format `<object>/<navigation>/<target_id>`.",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+
+			"object": schema.StringAttribute{
+				Required:    true,
+				Description: "The path of the object that owns the navigation property, e.g. `groups/{id}`.",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+
+			"navigation": schema.StringAttribute{
+				Required:    true,
+				Description: "The navigation property to link through, e.g. `members` or `owners`.",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+
+			"target_id": schema.StringAttribute{
+				Required:    true,
+				Description: "The ID of the object to link to.",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+
+			"target_collection": schema.StringAttribute{
+				Optional:    true,
+				Computed:    true,
+				Default:     stringdefault.StaticString(defaultReferenceTargetCollection),
+				Description: "The collection of the object to link to, default is `directoryObjects`.",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+
+			"api_version": schema.StringAttribute{
+				Optional:    true,
+				Description: "Override the provider Microsoft Graph API version.",
+			},
+		},
+	}
+}
+
+func (r *msGraphReferenceResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var model msGraphReferenceResourceModel
+	diags := req.Plan.Get(ctx, &model)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	references := model.referenceClient(r.client)
+
+	resp.Diagnostics.Append(references.add(ctx, model.TargetID.ValueString())...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	model.ID = id.New(references.navigationPath(), model.TargetID.ValueString()).AsString()
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, model)...)
+}
+
+func (r *msGraphReferenceResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var model msGraphReferenceResourceModel
+	diags := req.State.Get(ctx, &model)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	found, diags := model.referenceClient(r.client).exists(ctx, model.TargetID.ValueString())
+	if diags.HasError() {
+		resp.Diagnostics.Append(diags...)
+		return
+	}
+
+	if !found {
+		resp.State.RemoveResource(ctx)
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, model)...)
+}
+
+func (r *msGraphReferenceResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var model msGraphReferenceResourceModel
+	diags := req.Plan.Get(ctx, &model)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, model)...)
+}
+
+func (r *msGraphReferenceResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var model msGraphReferenceResourceModel
+	diags := req.State.Get(ctx, &model)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(model.referenceClient(r.client).remove(ctx, model.TargetID.ValueString())...)
+}
+
+func (r *msGraphReferenceResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	referenceID, diags := ensureParseReferenceID(req.ID)
+	if diags.HasError() {
+		resp.Diagnostics.Append(diags...)
+		return
+	}
+
+	model := msGraphReferenceResourceModel{
+		Object:           types.StringValue(referenceID.object),
+		Navigation:       types.StringValue(referenceID.navigation),
+		TargetID:         types.StringValue(referenceID.targetID),
+		TargetCollection: types.StringValue(defaultReferenceTargetCollection),
+		ApiVersion:       types.StringNull(),
+	}
+
+	if referenceID.apiVersion != "" {
+		model.ApiVersion = types.StringValue(referenceID.apiVersion)
+	}
+
+	model.ID = id.New(model.referenceClient(r.client).navigationPath(), referenceID.targetID).AsString()
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, model)...)
+}
+
+func (model *msGraphReferenceResourceModel) referenceClient(client client.MsGraphClient) *referenceClient {
+	return newReferenceClient(client, model.ApiVersion, model.Object.ValueString(), model.Navigation.ValueString(), model.TargetCollection.ValueString())
+}
@@ -0,0 +1,68 @@
+package msgraph
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/acctest"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/plancheck"
+)
+
+func TestAccMsGraphReferenceResource(t *testing.T) {
+	const resourceName = "msgraph_reference.owner"
+	groupName := acctest.RandString(10)
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: protoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: msGraphReferenceResourceConfig(groupName),
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectResourceAction(resourceName, plancheck.ResourceActionCreate),
+					},
+				},
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttrSet(resourceName, "id"),
+					resource.TestCheckResourceAttr(resourceName, "navigation", "owners"),
+					resource.TestCheckResourceAttr(resourceName, "target_collection", "directoryObjects"),
+				),
+			},
+			{
+				Config: msGraphReferenceResourceConfig(groupName),
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectResourceAction(resourceName, plancheck.ResourceActionNoop),
+					},
+				},
+			},
+			{
+				ResourceName:      resourceName,
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
+
+func msGraphReferenceResourceConfig(groupName string) string {
+	return defaultProviderConfigWith(`
+	data "msgraph_provider_config" "this" {}
+
+	resource "msgraph_object" "group" {
+		collection = "groups"
+		properties = {
+			displayName = "%[1]s"
+			mailEnabled = false
+			mailNickname = "%[1]s"
+			securityEnabled = true
+		}
+	}
+
+	resource "msgraph_reference" "owner" {
+		object     = msgraph_object.group.id
+		navigation = "owners"
+		target_id  = data.msgraph_provider_config.this.object_id
+	}
+	`, groupName)
+}
@@ -0,0 +1,223 @@
+package msgraph
+
+import (
+	"context"
+
+	"github.com/GoodCloudWorks/terraform-provider-msgraph/msgraph/client"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringdefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+var (
+	_ resource.Resource                = &msGraphReferencesResource{}
+	_ resource.ResourceWithConfigure   = &msGraphReferencesResource{}
+	_ resource.ResourceWithImportState = &msGraphReferencesResource{}
+)
+
+type msGraphReferencesResource struct {
+	client client.MsGraphClient
+}
+
+type msGraphReferencesResourceModel struct {
+	ID               types.String `tfsdk:"id"`
+	Object           types.String `tfsdk:"object"`
+	Navigation       types.String `tfsdk:"navigation"`
+	TargetIDs        types.Set    `tfsdk:"target_ids"`
+	TargetCollection types.String `tfsdk:"target_collection"`
+	ApiVersion       types.String `tfsdk:"api_version"`
+}
+
+func NewMsGraphReferencesResource() resource.Resource {
+	return &msGraphReferencesResource{}
+}
+
+func (r *msGraphReferencesResource) Configure(_ context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	if v, ok := req.ProviderData.(client.MsGraphClient); ok {
+		r.client = v
+	}
+}
+
+func (*msGraphReferencesResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_references"
+}
+
+func (*msGraphReferencesResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "This resource provides the ability to authoritatively manage all `$ref` links of a Microsoft Graph navigation property, such as the members or owners of a group. Links not present in `target_ids` are removed.",
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Computed:    true,
+				Description: "The ID of the references, in the format `<object>/<navigation>`.",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+
+			"object": schema.StringAttribute{
+				Required:    true,
+				Description: "The path of the object that owns the navigation property, e.g. `groups/{id}`.",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+
+			"navigation": schema.StringAttribute{
+				Required:    true,
+				Description: "The navigation property to link through, e.g. `members` or `owners`.",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+
+			"target_ids": schema.SetAttribute{
+				Required:    true,
+				ElementType: types.StringType,
+				Description: "The IDs of the objects to link to.",
+			},
+
+			"target_collection": schema.StringAttribute{
+				Optional:    true,
+				Computed:    true,
+				Default:     stringdefault.StaticString(defaultReferenceTargetCollection),
+				Description: "The collection of the objects to link to, default is `directoryObjects`.",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+
+			"api_version": schema.StringAttribute{
+				Optional:    true,
+				Description: "Override the provider Microsoft Graph API version.",
+			},
+		},
+	}
+}
+
+func (r *msGraphReferencesResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var model msGraphReferencesResourceModel
+	diags := req.Plan.Get(ctx, &model)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	references := model.referenceClient(r.client)
+
+	resp.Diagnostics.Append(r.reconcile(ctx, references, model.TargetIDs)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	model.ID = types.StringValue(references.navigationPath())
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, model)...)
+}
+
+func (r *msGraphReferencesResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var model msGraphReferencesResourceModel
+	diags := req.State.Get(ctx, &model)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	targetIDs, diags := model.referenceClient(r.client).list(ctx)
+	if diags.HasError() {
+		resp.Diagnostics.Append(diags...)
+		return
+	}
+
+	model.TargetIDs, diags = stringsAsSet(targetIDs)
+	if diags.HasError() {
+		resp.Diagnostics.Append(diags...)
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, model)...)
+}
+
+func (r *msGraphReferencesResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var model msGraphReferencesResourceModel
+	diags := req.Plan.Get(ctx, &model)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(r.reconcile(ctx, model.referenceClient(r.client), model.TargetIDs)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, model)...)
+}
+
+func (r *msGraphReferencesResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var model msGraphReferencesResourceModel
+	diags := req.State.Get(ctx, &model)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(model.referenceClient(r.client).removeAll(ctx, setAsStrings(model.TargetIDs))...)
+}
+
+func (r *msGraphReferencesResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	id, diags := ensureParseID(req.ID)
+	if diags.HasError() {
+		resp.Diagnostics.Append(diags...)
+		return
+	}
+
+	model := msGraphReferencesResourceModel{
+		ID:               id.AsString(),
+		Object:           types.StringValue(id.Collection()),
+		Navigation:       types.StringValue(id.ObjectId()),
+		TargetIDs:        types.SetNull(types.StringType),
+		TargetCollection: types.StringValue(defaultReferenceTargetCollection),
+		ApiVersion:       types.StringNull(),
+	}
+
+	if apiVersion := id.ApiVersion(); apiVersion != "" {
+		model.ApiVersion = types.StringValue(apiVersion)
+	}
+
+	targetIDs, diags := model.referenceClient(r.client).list(ctx)
+	if diags.HasError() {
+		resp.Diagnostics.Append(diags...)
+		return
+	}
+
+	model.TargetIDs, diags = stringsAsSet(targetIDs)
+	if diags.HasError() {
+		resp.Diagnostics.Append(diags...)
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, model)...)
+}
+
+func (r *msGraphReferencesResource) reconcile(ctx context.Context, references *referenceClient, targetIDs types.Set) diag.Diagnostics {
+	current, diags := references.list(ctx)
+	if diags.HasError() {
+		return diags
+	}
+
+	toAdd, toRemove := diffReferences(current, setAsStrings(targetIDs))
+
+	if diags := references.addAll(ctx, toAdd); diags.HasError() {
+		return diags
+	}
+
+	return references.removeAll(ctx, toRemove)
+}
+
+func (model *msGraphReferencesResourceModel) referenceClient(client client.MsGraphClient) *referenceClient {
+	return newReferenceClient(client, model.ApiVersion, model.Object.ValueString(), model.Navigation.ValueString(), model.TargetCollection.ValueString())
+}
@@ -0,0 +1,87 @@
+package msgraph
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/acctest"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/plancheck"
+)
+
+func TestAccMsGraphReferencesResource(t *testing.T) {
+	const resourceName = "msgraph_references.members"
+	groupName := acctest.RandString(10)
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: protoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: msGraphReferencesResourceConfig(groupName, 1),
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectResourceAction(resourceName, plancheck.ResourceActionCreate),
+					},
+				},
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttrSet(resourceName, "id"),
+					resource.TestCheckResourceAttr(resourceName, "target_ids.#", "1"),
+				),
+			},
+			{
+				Config: msGraphReferencesResourceConfig(groupName, 2),
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectResourceAction(resourceName, plancheck.ResourceActionUpdate),
+					},
+				},
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr(resourceName, "target_ids.#", "2"),
+				),
+			},
+			{
+				Config: msGraphReferencesResourceConfig(groupName, 2),
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectResourceAction(resourceName, plancheck.ResourceActionNoop),
+					},
+				},
+			},
+			{
+				ResourceName:      resourceName,
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
+
+func msGraphReferencesResourceConfig(groupName string, memberCount int) string {
+	return defaultProviderConfigWith(`
+	resource "msgraph_object" "group" {
+		collection = "groups"
+		properties = {
+			displayName = "%[1]s"
+			mailEnabled = false
+			mailNickname = "%[1]s"
+			securityEnabled = true
+		}
+	}
+
+	resource "msgraph_object" "member" {
+		count      = 2
+		collection = "groups"
+		properties = {
+			displayName = "%[1]s-member-${count.index}"
+			mailEnabled = false
+			mailNickname = "%[1]s-member-${count.index}"
+			securityEnabled = true
+		}
+	}
+
+	resource "msgraph_references" "members" {
+		object     = msgraph_object.group.id
+		navigation = "members"
+		target_ids = [for member in slice(msgraph_object.member, 0, %[2]d) : member.output.id]
+	}
+	`, groupName, memberCount)
+}
@@ -0,0 +1,332 @@
+package msgraph
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+
+	"github.com/GoodCloudWorks/terraform-provider-msgraph/msgraph/client"
+	"github.com/GoodCloudWorks/terraform-provider-msgraph/msgraph/dynamic"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/booldefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+const (
+	privateStateOriginalProperties = "original_properties"
+
+	// The properties that the object did not have before they were first
+	// updated, which are not restored.
+	privateStateAbsentProperties = "absent_properties"
+)
+
+var (
+	_ resource.Resource              = &msGraphUpdateResource{}
+	_ resource.ResourceWithConfigure = &msGraphUpdateResource{}
+)
+
+type privateState interface {
+	GetKey(ctx context.Context, key string) ([]byte, diag.Diagnostics)
+}
+
+type msGraphUpdateResource struct {
+	client client.MsGraphClient
+}
+
+type msGraphUpdateResourceModel struct {
+	ID               types.String  `tfsdk:"id"`
+	ApiVersion       types.String  `tfsdk:"api_version"`
+	Properties       types.Dynamic `tfsdk:"properties"`
+	RestoreOnDestroy types.Bool    `tfsdk:"restore_on_destroy"`
+	Output           types.Dynamic `tfsdk:"output"`
+}
+
+func NewMsGraphUpdateResource() resource.Resource {
+	return &msGraphUpdateResource{}
+}
+
+func (r *msGraphUpdateResource) Configure(_ context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	if v, ok := req.ProviderData.(client.MsGraphClient); ok {
+		r.client = v
+	}
+}
+
+func (*msGraphUpdateResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_update"
+}
+
+func (*msGraphUpdateResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "This resource provides the ability to update properties of a pre-existing Microsoft Graph object, such as a singleton or tenant-wide setting, without creating or deleting it.",
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Required:    true,
+				Description: "The path of the object to update, e.g. `policies/authorizationPolicy`.",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+
+			"api_version": schema.StringAttribute{
+				Optional:    true,
+				Description: "Override the provider Microsoft Graph API version.",
+			},
+
+			"properties": schema.DynamicAttribute{
+				Required:    true,
+				Description: "The properties to update on the object. Unlike `msgraph_object`, the order of the elements of arrays is significant.",
+				PlanModifiers: []planmodifier.Dynamic{
+					dynamic.UseStateWhen(dynamic.SemanticallyEqual),
+					dynamic.KnownWhereConfigured(),
+				},
+			},
+
+			"restore_on_destroy": schema.BoolAttribute{
+				Optional:    true,
+				Computed:    true,
+				Default:     booldefault.StaticBool(false),
+				Description: "Restore the values the properties had before they were first updated when the resource is destroyed, properties the object did not have are left as they are, default is `false`.",
+			},
+
+			"output": schema.DynamicAttribute{
+				Computed:    true,
+				Description: "The object retrieved from Microsoft Graph.",
+			},
+		},
+	}
+}
+
+func (r *msGraphUpdateResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var model msGraphUpdateResourceModel
+	diags := req.Plan.Get(ctx, &model)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	original, absent, diags := r.captureOriginalProperties(ctx, model, resp.Private)
+	if diags.HasError() {
+		resp.Diagnostics.Append(diags...)
+		return
+	}
+
+	resp.Diagnostics.Append(resp.Private.SetKey(ctx, privateStateOriginalProperties, original)...)
+	resp.Diagnostics.Append(resp.Private.SetKey(ctx, privateStateAbsentProperties, absent)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	model.Output, diags = r.patch(ctx, model)
+	if diags.HasError() {
+		resp.Diagnostics.Append(diags...)
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, model)...)
+}
+
+func (r *msGraphUpdateResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var model msGraphUpdateResourceModel
+	diags := req.State.Get(ctx, &model)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	id, diags := ensureParseIDString(model.ID)
+	if diags.HasError() {
+		resp.Diagnostics.Append(diags...)
+		return
+	}
+
+	http := r.client.R(ctx, model.ApiVersion)
+
+	content, found, diags := ensureFindObjectAsDynamic(http, id.Path)
+	if diags.HasError() {
+		resp.Diagnostics.Append(diags...)
+		return
+	}
+
+	if !found {
+		resp.Diagnostics.AddWarning(
+			fmt.Sprintf("Object %q not found, removing it from state.", id.Path),
+			notFoundDetail(ctx, r.client, model.ApiVersion, id),
+		)
+		resp.State.RemoveResource(ctx)
+		return
+	}
+	model.Output = content
+
+	properties, err := dynamic.UpdateWithSchemaPreservation(content, model.Properties, dynamic.UpdateOptions{})
+	if err != nil {
+		resp.Diagnostics.Append(errorDiagnostics("Failed to apply dynamic properties.", err.Error())...)
+		return
+	}
+	model.Properties = properties
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, model)...)
+}
+
+func (r *msGraphUpdateResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var model msGraphUpdateResourceModel
+	diags := req.Plan.Get(ctx, &model)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	original, absent, diags := r.captureOriginalProperties(ctx, model, req.Private)
+	if diags.HasError() {
+		resp.Diagnostics.Append(diags...)
+		return
+	}
+
+	resp.Diagnostics.Append(resp.Private.SetKey(ctx, privateStateOriginalProperties, original)...)
+	resp.Diagnostics.Append(resp.Private.SetKey(ctx, privateStateAbsentProperties, absent)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	model.Output, diags = r.patch(ctx, model)
+	if diags.HasError() {
+		resp.Diagnostics.Append(diags...)
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, model)...)
+}
+
+func (r *msGraphUpdateResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var model msGraphUpdateResourceModel
+	diags := req.State.Get(ctx, &model)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if !model.RestoreOnDestroy.ValueBool() {
+		return
+	}
+
+	original, diags := req.Private.GetKey(ctx, privateStateOriginalProperties)
+	if diags.HasError() {
+		resp.Diagnostics.Append(diags...)
+		return
+	}
+
+	if len(original) == 0 {
+		resp.Diagnostics.AddWarning("Original properties are unknown.", "The properties were not restored because their original values were not captured.")
+		return
+	}
+
+	// Properties that the object did not have are not captured, as they
+	// cannot be removed again.
+	if string(original) == "{}" {
+		return
+	}
+
+	id, diags := ensureParseIDString(model.ID)
+	if diags.HasError() {
+		resp.Diagnostics.Append(diags...)
+		return
+	}
+
+	http := r.client.R(ctx, model.ApiVersion)
+	setRequestJSONBody(http, original)
+
+	response, err := patch(http, id.Path)
+	resp.Diagnostics.Append(ensureHttpResponseSucceeded(response, err)...)
+}
+
+// captureOriginalProperties returns the current values of every property that
+// has not been captured before, merged with the previously captured values,
+// and the properties that the object does not have.
+func (r *msGraphUpdateResource) captureOriginalProperties(ctx context.Context, model msGraphUpdateResourceModel, private privateState) ([]byte, []byte, diag.Diagnostics) {
+	id, diags := ensureParseIDString(model.ID)
+	if diags.HasError() {
+		return nil, nil, diags
+	}
+
+	captured, diags := private.GetKey(ctx, privateStateOriginalProperties)
+	if diags.HasError() {
+		return nil, nil, diags
+	}
+
+	original, diags := ensureJSONAsMap(captured)
+	if diags.HasError() {
+		return nil, nil, diags
+	}
+
+	capturedAbsent, diags := private.GetKey(ctx, privateStateAbsentProperties)
+	if diags.HasError() {
+		return nil, nil, diags
+	}
+
+	var absent []string
+	if len(capturedAbsent) > 0 {
+		if err := json.Unmarshal(capturedAbsent, &absent); err != nil {
+			return nil, nil, errorDiagnostics("Failed to unmarshal absent properties.", err.Error())
+		}
+	}
+
+	http := r.client.R(ctx, model.ApiVersion)
+
+	content, diags := ensureGetObjectAsDynamic(http, id.Path)
+	if diags.HasError() {
+		return nil, nil, diags
+	}
+
+	current, missing, diags := ensurePickProperties(content, model.Properties)
+	if diags.HasError() {
+		return nil, nil, diags
+	}
+
+	for key, value := range current {
+		if _, ok := original[key]; !ok && !slices.Contains(absent, key) {
+			original[key] = value
+		}
+	}
+
+	for _, key := range missing {
+		if _, ok := original[key]; !ok && !slices.Contains(absent, key) {
+			absent = append(absent, key)
+		}
+	}
+
+	result, err := json.Marshal(original)
+	if err != nil {
+		return nil, nil, errorDiagnostics("Failed to marshal original properties to JSON.", err.Error())
+	}
+
+	absentResult, err := json.Marshal(absent)
+	if err != nil {
+		return nil, nil, errorDiagnostics("Failed to marshal absent properties to JSON.", err.Error())
+	}
+
+	return result, absentResult, noErrors()
+}
+
+func (r *msGraphUpdateResource) patch(ctx context.Context, model msGraphUpdateResourceModel) (types.Dynamic, diag.Diagnostics) {
+	id, diags := ensureParseIDString(model.ID)
+	if diags.HasError() {
+		return types.DynamicNull(), diags
+	}
+
+	http := r.client.R(ctx, model.ApiVersion)
+
+	if diags := ensureRequestSetBodyFromDynamic(http, model.Properties); diags.HasError() {
+		return types.DynamicNull(), diags
+	}
+
+	response, err := patch(http, id.Path)
+	if diags := ensurePropertiesResponseSucceeded(response, err); diags.HasError() {
+		return types.DynamicNull(), diags
+	}
+
+	return ensureGetObjectAsDynamic(r.client.R(ctx, model.ApiVersion), id.Path)
+}
@@ -0,0 +1,75 @@
+package msgraph
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/acctest"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/plancheck"
+)
+
+func TestAccMsGraphUpdateResource(t *testing.T) {
+	const resourceName = "msgraph_update.group"
+	groupName := acctest.RandString(10)
+	description := acctest.RandString(10)
+	updatedDescription := description + "-updated"
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: protoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: msGraphUpdateResourceConfig(groupName, description),
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectResourceAction(resourceName, plancheck.ResourceActionCreate),
+					},
+				},
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr(resourceName, "output.description", description),
+					resource.TestCheckResourceAttr(resourceName, "output.displayName", groupName),
+				),
+			},
+			{
+				Config: msGraphUpdateResourceConfig(groupName, description),
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectResourceAction(resourceName, plancheck.ResourceActionNoop),
+					},
+				},
+			},
+			{
+				Config: msGraphUpdateResourceConfig(groupName, updatedDescription),
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectResourceAction(resourceName, plancheck.ResourceActionUpdate),
+					},
+				},
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr(resourceName, "output.description", updatedDescription),
+				),
+			},
+		},
+	})
+}
+
+func msGraphUpdateResourceConfig(groupName string, description string) string {
+	return defaultProviderConfigWith(`
+	resource "msgraph_object" "group" {
+		collection = "groups"
+		properties = {
+			displayName = "%[1]s"
+			mailEnabled = false
+			mailNickname = "%[1]s"
+			securityEnabled = true
+		}
+	}
+
+	resource "msgraph_update" "group" {
+		id = msgraph_object.group.id
+		properties = {
+			description = "%[2]s"
+		}
+		restore_on_destroy = true
+	}
+	`, groupName, description)
+}
@@ -0,0 +1,34 @@
+package msgraph
+
+import (
+	"context"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+)
+
+const (
+	// Microsoft Graph reads are eventually consistent, so an object created by
+	// a failed request is only considered absent after several reads.
+	retryProbeReads    = 3
+	retryProbeInterval = 5 * time.Second
+)
+
+// isConsistentlyAbsent reports whether `find` found nothing in every one of
+// several reads, each made after waiting for replication to catch up.
+func isConsistentlyAbsent(ctx context.Context, find func(ctx context.Context) (bool, diag.Diagnostics)) bool {
+	for i := 0; i < retryProbeReads; i++ {
+		select {
+		case <-ctx.Done():
+			return false
+		case <-time.After(retryProbeInterval):
+		}
+
+		found, diags := find(ctx)
+		if diags.HasError() || found {
+			return false
+		}
+	}
+
+	return true
+}
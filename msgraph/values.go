@@ -0,0 +1,113 @@
+package msgraph
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/GoodCloudWorks/terraform-provider-msgraph/msgraph/dynamic"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func listAsStrings(value types.List) []string {
+	var values []string
+	for _, element := range value.Elements() {
+		values = append(values, element.(types.String).ValueString())
+	}
+	return values
+}
+
+func setAsStrings(value types.Set) []string {
+	var values []string
+	for _, element := range value.Elements() {
+		values = append(values, element.(types.String).ValueString())
+	}
+	return values
+}
+
+func stringsAsSet(values []string) (types.Set, diag.Diagnostics) {
+	elements := make([]attr.Value, 0, len(values))
+	for _, value := range values {
+		elements = append(elements, types.StringValue(value))
+	}
+	return types.SetValue(types.StringType, elements)
+}
+
+// ensureListAsPaths parses the JSON pointers of the `attribute` list. Unknown
+// elements are skipped.
+func ensureListAsPaths(value types.List, attribute string) ([]dynamic.Path, diag.Diagnostics) {
+	var paths []dynamic.Path
+	for _, element := range value.Elements() {
+		if element.IsUnknown() {
+			continue
+		}
+
+		parsed, err := dynamic.ParsePath(element.(types.String).ValueString())
+		if err != nil {
+			return nil, diag.Diagnostics{
+				diag.NewAttributeErrorDiagnostic(path.Root(attribute), "Invalid JSON pointer.", err.Error()),
+			}
+		}
+		paths = append(paths, parsed)
+	}
+	return paths, noErrors()
+}
+
+// ensureMapAsNormalizers parses the map of JSON pointers to built-in normalizer
+// names of the `attribute` map. Unknown elements are skipped and null elements
+// are rejected.
+func ensureMapAsNormalizers(value types.Map, attribute string) ([]dynamic.PathNormalizer, diag.Diagnostics) {
+	var normalizers []dynamic.PathNormalizer
+	for pointer, element := range value.Elements() {
+		if element.IsUnknown() {
+			continue
+		}
+
+		parsed, err := dynamic.ParsePath(pointer)
+		if err != nil {
+			return nil, diag.Diagnostics{
+				diag.NewAttributeErrorDiagnostic(path.Root(attribute).AtMapKey(pointer), "Invalid JSON pointer.", err.Error()),
+			}
+		}
+
+		if element.IsNull() {
+			return nil, diag.Diagnostics{
+				diag.NewAttributeErrorDiagnostic(path.Root(attribute).AtMapKey(pointer), "Missing normalizer.", fmt.Sprintf("The normalizer must not be null, it must be one of %s.", strings.Join(dynamic.NormalizerNames(), ", "))),
+			}
+		}
+
+		normalizer, err := dynamic.ParseNormalizer(element.(types.String).ValueString())
+		if err != nil {
+			return nil, diag.Diagnostics{
+				diag.NewAttributeErrorDiagnostic(path.Root(attribute).AtMapKey(pointer), "Invalid normalizer.", err.Error()),
+			}
+		}
+
+		normalizers = append(normalizers, dynamic.PathNormalizer{Path: parsed, Normalizer: normalizer})
+	}
+	return normalizers, noErrors()
+}
+
+// ensureMapAsArrayKeys parses the map of JSON pointers of arrays to the names
+// of the properties identifying their elements of the `attribute` map. Unknown
+// elements are skipped.
+func ensureMapAsArrayKeys(value types.Map, attribute string) ([]dynamic.ArrayKey, diag.Diagnostics) {
+	var keys []dynamic.ArrayKey
+	for pointer, element := range value.Elements() {
+		if element.IsUnknown() {
+			continue
+		}
+
+		parsed, err := dynamic.ParsePath(pointer)
+		if err != nil {
+			return nil, diag.Diagnostics{
+				diag.NewAttributeErrorDiagnostic(path.Root(attribute).AtMapKey(pointer), "Invalid JSON pointer.", err.Error()),
+			}
+		}
+
+		keys = append(keys, dynamic.ArrayKey{Path: parsed, Key: element.(types.String).ValueString()})
+	}
+	return keys, noErrors()
+}